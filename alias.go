@@ -0,0 +1,72 @@
+package ini
+
+import "fmt"
+
+// AliasSection makes the section alias another name for the section target,
+// so that code and files using a section's old name keep working after it was
+// renamed. Afterwards both names refer to the same Section: Get and Set with
+// either name read and write the same values.
+//
+// Keys that the file has under alias are moved to target, keys that exist in
+// both keep the value from target. Writing the File writes the keys under
+// both names, which keeps older programs that read the alias working. Use
+// delete(f, alias) before writing to drop the old name.
+func (f File) AliasSection(alias, target string) {
+	if alias == target {
+		return
+	}
+	s := f.Section(target)
+	for key, value := range f[alias] {
+		if _, ok := s[key]; !ok {
+			s[key] = value
+		}
+	}
+	f[alias] = s
+}
+
+// KeyAlias makes reading store a key named alias in section under the name
+// key, e.g. for a key that was renamed from max_conns to max_connections:
+//
+//	ini.KeyAlias("server", "max_conns", "max_connections")
+//
+// If a file contains both names, the value of key is used, regardless of the
+// order, and a Warning is reported, see OnWarning. Use the option several times
+// for several aliases. The names are compared after CaseInsensitiveSections
+// and CaseInsensitiveKeys are applied, so pass them in lower case with these
+// options.
+func KeyAlias(section, alias, key string) Option {
+	return func(o *options) {
+		if o.keyAliases == nil {
+			o.keyAliases = make(map[[2]string]string)
+		}
+		o.keyAliases[[2]string{section, alias}] = key
+	}
+}
+
+// resolveKeyAlias renames a key node for an alias to its key. It returns false
+// if the node must be ignored because the key itself is also set.
+func (b *fileBuilder) resolveKeyAlias(n *Node) bool {
+	s := b.file[n.Section]
+	if key, ok := b.opts.keyAliases[[2]string{n.Section, n.Key}]; ok {
+		id := [2]string{n.Section, key}
+		if _, exists := s[key]; exists && b.aliased[id].Key == "" {
+			b.opts.warning(Warning{n.Line, n.Section, n.Key,
+				fmt.Sprintf("ignored because %s is also set", key)})
+			return false
+		}
+		if b.aliased == nil {
+			b.aliased = make(map[[2]string]Node)
+		}
+		b.aliased[id] = *n
+		n.Key = key
+		return true
+	}
+	id := [2]string{n.Section, n.Key}
+	if alias, ok := b.aliased[id]; ok {
+		b.opts.warning(Warning{alias.Line, alias.Section, alias.Key,
+			fmt.Sprintf("ignored because %s is also set", n.Key)})
+		delete(s, n.Key)
+		delete(b.aliased, id)
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package ini
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAliasSection(t *testing.T) {
+	f := MustParse("[database]\nhost = old\nuser = admin\n[db]\nhost = new")
+	f.AliasSection("database", "db")
+
+	for _, section := range []string{"db", "database"} {
+		if v, _ := f.Get(section, "host"); v != "new" {
+			t.Errorf("%s: expected host from target, got %q", section, v)
+		}
+		if v, _ := f.Get(section, "user"); v != "admin" {
+			t.Errorf("%s: expected user moved from alias, got %q", section, v)
+		}
+	}
+	f.Set("database", "port", "5432")
+	if v, _ := f.Get("db", "port"); v != "5432" {
+		t.Errorf("expected Set on alias to change target, got %q", v)
+	}
+
+	delete(f, "database")
+	var buf strings.Builder
+	f.WriteTo(&buf)
+	if buf.String() != "[db]\nhost = new\nport = 5432\nuser = admin\n" {
+		t.Errorf("unexpected output\n%s", buf.String())
+	}
+
+	g := make(File)
+	g.AliasSection("old", "new")
+	g.Set("old", "a", "1")
+	if v, _ := g.Get("new", "a"); v != "1" {
+		t.Errorf("expected alias of a new section to work, got %q", v)
+	}
+	g.AliasSection("new", "new")
+	if _, ok := g["new"]; !ok {
+		t.Error("aliasing a section to itself must not remove it")
+	}
+}
+
+func TestKeyAlias(t *testing.T) {
+	var warnings []string
+	opts := []Option{
+		KeyAlias("server", "max_conns", "max_connections"),
+		KeyAlias("", "colour", "color"),
+		OnWarning(func(w Warning) { warnings = append(warnings, w.String()) }),
+	}
+	check := func(src string, expect File, expectWarnings ...string) {
+		t.Helper()
+		warnings = nil
+		f, err := Read(strings.NewReader(src), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(f, expect) {
+			t.Errorf("expected %v, got %v", expect, f)
+		}
+		if !reflect.DeepEqual(warnings, expectWarnings) {
+			t.Errorf("expected warnings %q, got %q", expectWarnings, warnings)
+		}
+	}
+
+	check("colour = red\n[server]\nmax_conns = 10",
+		File{"": {"color": "red"}, "server": {"max_connections": "10"}})
+	check("[server]\nmax_conns = 10\nmax_connections = 20",
+		File{"server": {"max_connections": "20"}},
+		"line 2: [server] max_conns: ignored because max_connections is also set")
+	check("[server]\nmax_connections = 20\nmax_conns = 10",
+		File{"server": {"max_connections": "20"}},
+		"line 3: [server] max_conns: ignored because max_connections is also set")
+	check("[other]\nmax_conns = 10", File{"other": {"max_conns": "10"}})
+
+	_, err := Read(strings.NewReader("[server]\nmax_conns = 1\nmax_connections = 2"),
+		KeyAlias("server", "max_conns", "max_connections"), OnDuplicate(DuplicateError))
+	if err != nil {
+		t.Errorf("an alias and its key are not duplicates, got %v", err)
+	}
+}
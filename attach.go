@@ -0,0 +1,100 @@
+package ini
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"weak"
+)
+
+// Files and Sections are plain maps, everything stored in them is a section
+// or a key. What does not fit, like all values of a repeated key, is attached
+// to the map instead and lives as long as the map does. Copying the sections
+// or keys into another map does not copy what is attached.
+
+// mapState is what is attached to a File or Section.
+type mapState struct {
+	sync.Mutex
+	values   map[string][]string          // all values of the keys of a Section
+	origins  map[string]keyOrigin         // where the keys of a Section were read
+	regexps  map[regexpKey]compiledRegexp // the expressions of a File, see GetRegexp
+	defaults map[string]map[string]string // the defaults of a File, see SetDefaults
+	order    *Order                       // the order of a File, see KeepOrder
+	bound    *BoundSection                // the file of a Section, see SetAndSave
+}
+
+var attached = struct {
+	sync.Mutex
+	states map[weak.Pointer[byte]]*mapState
+}{states: make(map[weak.Pointer[byte]]*mapState)}
+
+// stateOf returns the state attached to m, which must be a File or Section.
+// If there is none, it returns nil or, if create is true, attaches a new one.
+// Nil maps have no state.
+func stateOf(m interface{}, create bool) *mapState {
+	p := (*byte)(reflect.ValueOf(m).UnsafePointer())
+	if p == nil {
+		return nil
+	}
+	id := weak.Make(p)
+	attached.Lock()
+	defer attached.Unlock()
+	s := attached.states[id]
+	if s == nil && create {
+		s = &mapState{}
+		attached.states[id] = s
+		runtime.AddCleanup(p, detach, id)
+	}
+	return s
+}
+
+// detach removes the state of a map that is no longer used.
+func detach(id weak.Pointer[byte]) {
+	attached.Lock()
+	delete(attached.states, id)
+	attached.Unlock()
+}
+
+// mergeState makes the keys that File.Merge copied from src to dst have the
+// values for GetAll and the origins from src.
+func mergeState(dst, src Section) {
+	from := stateOf(src, false)
+	to := stateOf(dst, from != nil)
+	if to == nil {
+		return
+	}
+	var values map[string][]string
+	var origins map[string]keyOrigin
+	if from != nil {
+		from.Lock()
+		values = make(map[string][]string, len(from.values))
+		for key, all := range from.values {
+			values[key] = all
+		}
+		origins = make(map[string]keyOrigin, len(from.origins))
+		for key, origin := range from.origins {
+			origins[key] = origin
+		}
+		from.Unlock()
+	}
+	to.Lock()
+	defer to.Unlock()
+	for key := range src {
+		if all, ok := values[key]; ok {
+			if to.values == nil {
+				to.values = make(map[string][]string)
+			}
+			to.values[key] = all
+		} else {
+			delete(to.values, key)
+		}
+		if origin, ok := origins[key]; ok {
+			if to.origins == nil {
+				to.origins = make(map[string]keyOrigin)
+			}
+			to.origins[key] = origin
+		} else {
+			delete(to.origins, key)
+		}
+	}
+}
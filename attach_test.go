@@ -0,0 +1,33 @@
+package ini
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestStateIsDetached(t *testing.T) {
+	count := func() int {
+		attached.Lock()
+		defer attached.Unlock()
+		return len(attached.states)
+	}
+	before := count()
+	for i := 0; i < 100; i++ {
+		s := Section{"k": "v"}
+		stateOf(s, true).values = map[string][]string{"k": {"v"}}
+		if stateOf(s, false) == nil {
+			t.Fatal("state was not attached")
+		}
+	}
+	for i := 0; i < 100 && count() > before; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := count(); n > before {
+		t.Errorf("%d states are still attached after their maps are gone", n-before)
+	}
+	if stateOf(Section(nil), true) != nil {
+		t.Error("nil maps must not have state")
+	}
+}
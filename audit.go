@@ -0,0 +1,64 @@
+package ini
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// An AuditRecord describes a change made through File.Set or File.Save, see
+// Audit.
+type AuditRecord struct {
+	Time time.Time
+	Who  string // as passed to Audit or AuditLog
+	Op   string // "set" or "save"
+
+	// Section, Key, Old and New describe the change made by Set. Existed tells
+	// whether the key had a value before, Old is only valid if it did.
+	Section string
+	Key     string
+	Old     string
+	New     string
+	Existed bool
+
+	// Path is the file written by Save.
+	Path string
+}
+
+func (r AuditRecord) String() string {
+	t := r.Time.Format(time.RFC3339)
+	if r.Op == "save" {
+		return fmt.Sprintf("%s %s save %q", t, r.Who, r.Path)
+	}
+	old := "<unset>"
+	if r.Existed {
+		old = fmt.Sprintf("%q", r.Old)
+	}
+	return fmt.Sprintf("%s %s set [%s] %s: %s -> %q", t, r.Who, r.Section, r.Key, old, r.New)
+}
+
+// Audit makes File.Set and File.Save report every change they make to log.
+// The records carry who as the actor, e.g. a user or program name.
+func Audit(who string, log func(AuditRecord)) Option {
+	return func(o *options) {
+		o.auditWho = who
+		o.auditLog = log
+	}
+}
+
+// AuditLog makes File.Set and File.Save append a line to w for every change
+// they make, formatted by AuditRecord.String.
+func AuditLog(who string, w io.Writer) Option {
+	return Audit(who, func(r AuditRecord) {
+		fmt.Fprintln(w, r)
+	})
+}
+
+func (o *options) audit(r AuditRecord) {
+	if o.auditLog == nil {
+		return
+	}
+	r.Time = time.Now()
+	r.Who = o.auditWho
+	o.auditLog(r)
+}
@@ -0,0 +1,111 @@
+package ini
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// GetBase64 decodes the value for a key in a section as standard base64, as
+// defined in RFC 4648.
+func (f File) GetBase64(section, key string) ([]byte, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrValue{section, key, value, err}
+	}
+	return data, nil
+}
+
+// SetBase64 stores data for a key in a section encoded as standard base64.
+func (f File) SetBase64(section, key string, data []byte, opts ...Option) {
+	f.Set(section, key, base64.StdEncoding.EncodeToString(data), opts...)
+}
+
+// GetHex decodes the value for a key in a section as hexadecimal digits.
+// Upper and lower case digits are accepted.
+func (f File) GetHex(section, key string) ([]byte, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, ErrValue{section, key, value, err}
+	}
+	return data, nil
+}
+
+// SetHex stores data for a key in a section encoded as lower case hexadecimal
+// digits.
+func (f File) SetHex(section, key string, data []byte, opts ...Option) {
+	f.Set(section, key, hex.EncodeToString(data), opts...)
+}
+
+// GetRegBinary decodes a value in the notation of Windows registry files,
+// comma-separated hexadecimal bytes prefixed with hex:, e.g.
+//
+//	key = hex:de,ad,be,ef
+//
+// Whitespace between the bytes is ignored, so values that were continued over
+// several lines (see BackslashContinuation) can be decoded.
+func (f File) GetRegBinary(section, key string) ([]byte, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := parseRegBinary(value)
+	if err != nil {
+		return nil, ErrValue{section, key, value, err}
+	}
+	return data, nil
+}
+
+func parseRegBinary(s string) ([]byte, error) {
+	if !strings.HasPrefix(strings.ToLower(s), "hex:") {
+		return nil, errors.New("missing hex: prefix")
+	}
+	s = strings.TrimSpace(s[len("hex:"):])
+	if s == "" {
+		return []byte{}, nil
+	}
+	parts := strings.Split(s, ",")
+	data := make([]byte, len(parts))
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if len(part) != 2 {
+			return nil, errors.New("bytes must be two hexadecimal digits")
+		}
+		b, err := strconv.ParseUint(part, 16, 8)
+		if err != nil {
+			return nil, errors.New("bytes must be two hexadecimal digits")
+		}
+		data[i] = byte(b)
+	}
+	return data, nil
+}
+
+// GetDWORD decodes a value in the notation of Windows registry files, eight
+// hexadecimal digits prefixed with dword:, e.g.
+//
+//	key = dword:00000001
+func (f File) GetDWORD(section, key string) (uint32, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return 0, err
+	}
+	if !strings.HasPrefix(strings.ToLower(value), "dword:") {
+		return 0, ErrValue{section, key, value, errors.New("missing dword: prefix")}
+	}
+	digits := value[len("dword:"):]
+	n, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil || len(digits) != 8 {
+		return 0, ErrValue{section, key, value, errors.New("expected eight hexadecimal digits")}
+	}
+	return uint32(n), nil
+}
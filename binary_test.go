@@ -0,0 +1,70 @@
+package ini
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBase64AndHex(t *testing.T) {
+	file := make(File)
+	salt := []byte{0xDE, 0xAD, 0xBE, 0xEF, 0x00}
+	file.SetBase64("crypto", "salt", salt)
+	file.SetHex("crypto", "key", salt)
+
+	if v, _ := file.Get("crypto", "salt"); v != "3q2+7wA=" {
+		t.Errorf("unexpected base64 encoding %q", v)
+	}
+	if v, _ := file.Get("crypto", "key"); v != "deadbeef00" {
+		t.Errorf("unexpected hex encoding %q", v)
+	}
+	if data, err := file.GetBase64("crypto", "salt"); err != nil || !bytes.Equal(data, salt) {
+		t.Errorf("GetBase64: got %v, %v", data, err)
+	}
+	if data, err := file.GetHex("crypto", "key"); err != nil || !bytes.Equal(data, salt) {
+		t.Errorf("GetHex: got %v, %v", data, err)
+	}
+
+	file.Set("crypto", "bad", "xyz")
+	if _, err := file.GetBase64("crypto", "bad"); err == nil {
+		t.Error("expected base64 error")
+	}
+	if _, err := file.GetHex("crypto", "bad"); err == nil {
+		t.Error("expected hex error")
+	}
+}
+
+func TestRegistryValues(t *testing.T) {
+	f := MustParse(`[HKEY_CURRENT_USER\Software\App]
+blob = hex:de,AD,be,ef
+long = hex:01,02,\
+  03,04
+empty = hex:
+flag = dword:0000002a
+short = dword:2a
+bad = hex:1,2`, Continuations(BackslashContinuation))
+	section := `HKEY_CURRENT_USER\Software\App`
+
+	check := func(key string, expect []byte) {
+		t.Helper()
+		data, err := f.GetRegBinary(section, key)
+		if err != nil || !bytes.Equal(data, expect) {
+			t.Errorf("%s: expected %x, got %x, %v", key, expect, data, err)
+		}
+	}
+	check("blob", []byte{0xDE, 0xAD, 0xBE, 0xEF})
+	check("long", []byte{1, 2, 3, 4})
+	check("empty", []byte{})
+	if _, err := f.GetRegBinary(section, "bad"); err == nil {
+		t.Error("expected error for single digit bytes")
+	}
+	if _, err := f.GetRegBinary(section, "flag"); err == nil {
+		t.Error("expected error for missing hex: prefix")
+	}
+
+	if n, err := f.GetDWORD(section, "flag"); err != nil || n != 42 {
+		t.Errorf("flag: got %v, %v", n, err)
+	}
+	if _, err := f.GetDWORD(section, "short"); err == nil {
+		t.Error("expected error for short dword")
+	}
+}
@@ -0,0 +1,165 @@
+package ini
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// A BoundSection is a section of a file on disk. Every SetAndSave changes the
+// file right away and only touches the line of the changed key, so tools that
+// tweak single settings often, like a tray app toggling options, keep the rest
+// of the file as the user wrote it. It is safe for concurrent use, all
+// BoundSections for the same file in a program take turns. This does not lock
+// the file against other programs, but the file is replaced as a whole so a
+// program reading it never sees a half written file.
+//
+// The Sections returned by Section stay bound to the file, see
+// Section.SetAndSave.
+type BoundSection struct {
+	path string
+	name string
+	opts []Option
+}
+
+// BindSection returns a BoundSection for the named section of the file at
+// path. The file does not need to exist yet, the first SetAndSave creates it.
+// The options are used for reading the file.
+func BindSection(path, section string, opts ...Option) *BoundSection {
+	return &BoundSection{path: path, name: section, opts: opts}
+}
+
+var fileLocks = struct {
+	sync.Mutex
+	locks map[string]*sync.Mutex
+}{locks: make(map[string]*sync.Mutex)}
+
+// lock locks the file of s against changes from other BoundSections and
+// returns the function to unlock it.
+func (s *BoundSection) lock() func() {
+	path, err := filepath.Abs(s.path)
+	if err != nil {
+		path = s.path
+	}
+	fileLocks.Lock()
+	l := fileLocks.locks[path]
+	if l == nil {
+		l = new(sync.Mutex)
+		fileLocks.locks[path] = l
+	}
+	fileLocks.Unlock()
+	l.Lock()
+	return l.Unlock
+}
+
+// Name returns the name of the section.
+func (s *BoundSection) Name() string {
+	return s.name
+}
+
+// Section reads the current contents of the section from disk. It is empty if
+// the file or section does not exist. The Section is bound to the file, its
+// SetAndSave is that of s.
+func (s *BoundSection) Section() (Section, error) {
+	defer s.lock()()
+	f, err := Load(s.path, s.opts...)
+	if os.IsNotExist(err) {
+		f, err = make(File), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	section := f.Section(s.name)
+	state := stateOf(section, true)
+	state.Lock()
+	state.bound = s
+	state.Unlock()
+	return section, nil
+}
+
+// ErrNotBound is returned by Section.SetAndSave for a Section that is not
+// bound to a file.
+var ErrNotBound = errors.New("ini: section is not bound to a file, see BindSection")
+
+// SetAndSave sets the value of key in the Section and in the file on disk that
+// it is bound to, see BoundSection.SetAndSave. Only Sections returned by
+// BoundSection.Section are bound to a file, others return ErrNotBound. The
+// Section is only changed if the file could be written.
+func (s Section) SetAndSave(key, value string) error {
+	var bound *BoundSection
+	if state := stateOf(s, false); state != nil {
+		state.Lock()
+		bound = state.bound
+		state.Unlock()
+	}
+	if bound == nil {
+		return ErrNotBound
+	}
+	if err := bound.SetAndSave(key, value); err != nil {
+		return err
+	}
+	s[key] = value
+	return nil
+}
+
+// SetAndSave sets the value of key in the file on disk, see Document.Set. The
+// file is only written if the value changes. The options of BindSection are
+// also used for writing, so Encrypted and Signed files stay encrypted and
+// signed.
+func (s *BoundSection) SetAndSave(key, value string) error {
+	defer s.lock()()
+	doc, err := LoadDocument(s.path, s.opts...)
+	if os.IsNotExist(err) {
+		// An empty Document, there is nothing to decrypt or verify yet.
+		doc = &Document{FinalNewline: true, LineBreak: "\n", opts: makeOptions(s.opts)}
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	if old, ok := doc.Get(s.name, key); ok && old == value {
+		return nil
+	}
+	if err := doc.Set(s.name, key, value); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		return err
+	}
+	return replaceFile(s.path, buf.Bytes())
+}
+
+// replaceFile writes data to a temporary file next to path and renames it to
+// path, so that path always holds either the old or the new contents. The file
+// keeps its permissions, new files are created with 0644.
+func replaceFile(path string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(mode)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
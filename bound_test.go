@@ -0,0 +1,142 @@
+package ini
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestBoundSection(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.ini")
+	writeFiles(t, dir, map[string]string{"app.ini": "; options\n[ui]\ndark   =   no\n\n[other]\nx = 1\n"})
+
+	ui := BindSection(path, "ui")
+	if err := ui.SetAndSave("dark", "yes"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ui.SetAndSave("size", "12"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "; options\n[ui]\ndark   =   yes\nsize = 12\n\n[other]\nx = 1\n"
+	if string(data) != expect {
+		t.Errorf("expected\n%s\nbut got\n%s", expect, data)
+	}
+	s, err := ui.Section()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, Section{"dark": "yes", "size": "12"}) {
+		t.Errorf("unexpected section %v", s)
+	}
+	if err := s.SetAndSave("dark", "no"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := ioutil.ReadFile(path); s["dark"] != "no" || !bytes.Contains(data, []byte("dark   =   no\n")) {
+		t.Errorf("expected the Section and the file to change, got %v and\n%s", s, data)
+	}
+	if err := (Section{"a": "b"}).SetAndSave("a", "c"); err != ErrNotBound {
+		t.Errorf("expected ErrNotBound, got %v", err)
+	}
+
+	newPath := filepath.Join(dir, "new.ini")
+	if s, err := BindSection(newPath, "a").Section(); err != nil || len(s) != 0 {
+		t.Errorf("expected empty section for missing file, got %v, %v", s, err)
+	}
+	var wg sync.WaitGroup
+	for _, key := range []string{"k1", "k2", "k3", "k4"} {
+		wg.Add(1)
+		go func(key string) {
+			defer wg.Done()
+			if err := BindSection(newPath, "a").SetAndSave(key, "v"); err != nil {
+				t.Error(err)
+			}
+		}(key)
+	}
+	wg.Wait()
+	f, err := Load(newPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f["a"], Section{"k1": "v", "k2": "v", "k3": "v", "k4": "v"}) {
+		t.Errorf("concurrent changes were lost: %v", f)
+	}
+}
+
+func TestBoundSectionEncryptedAndSigned(t *testing.T) {
+	c, err := AESGCM(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	tests := []struct {
+		name      string
+		opts      []Option
+		encrypted bool
+	}{
+		{"encrypted.ini", []Option{Encrypted(c)}, true},
+		{"signed.ini", []Option{Signed([]byte("secret"))}, false},
+		{"both.ini", []Option{Encrypted(c), Signed([]byte("secret"))}, true},
+	}
+	for _, test := range tests {
+		path := filepath.Join(dir, test.name)
+		db := BindSection(path, "db", test.opts...)
+		if err := db.SetAndSave("password", "hunter2"); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SetAndSave("user", "admin"); err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.encrypted && bytes.Contains(data, []byte("hunter2")) {
+			t.Errorf("%s: password written in plain text: %q", test.name, data)
+		}
+		f, err := Load(path, test.opts...)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(f["db"], Section{"password": "hunter2", "user": "admin"}) {
+			t.Errorf("%s: unexpected section %v", test.name, f["db"])
+		}
+	}
+}
+
+func TestBoundSectionKeepsMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.ini")
+	if err := ioutil.WriteFile(path, []byte("[a]\nx = 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := BindSection(path, "a").SetAndSave("x", "2"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600 but got %v", info.Mode().Perm())
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected only the ini file but found %d files", len(files))
+	}
+}
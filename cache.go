@@ -0,0 +1,75 @@
+package ini
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// A Cache maps paths to parsed Files. Cache.Load only parses a file again if
+// its modification time or size changed since it was last loaded. The zero
+// value is an empty cache that is ready to use. A Cache is safe for concurrent
+// use by multiple goroutines.
+//
+// Files returned from a Cache are shared between callers and must not be
+// modified.
+type Cache struct {
+	// Options are passed to Load when a file is parsed.
+	Options []Option
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	modTime time.Time
+	size    int64
+	file    File
+}
+
+// NewCache returns an empty Cache which parses files with the given options.
+func NewCache(opts ...Option) *Cache {
+	return &Cache{Options: opts}
+}
+
+// Load returns the File at path, from the cache if the file on disk did not
+// change since it was last loaded.
+func (c *Cache) Load(path string) (File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		c.Forget(path)
+		return nil, err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	c.mu.Unlock()
+	if ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		return entry.file, nil
+	}
+
+	file, err := Load(path, c.Options...)
+	if err != nil {
+		c.Forget(path)
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]cacheEntry)
+	}
+	c.entries[path] = cacheEntry{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		file:    file,
+	}
+	return file, nil
+}
+
+// Forget removes path from the cache.
+func (c *Cache) Forget(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, path)
+}
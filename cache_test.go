@@ -0,0 +1,47 @@
+package ini
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.ini")
+	write := func(src string) {
+		t.Helper()
+		if err := ioutil.WriteFile(path, []byte(src), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var cache Cache
+
+	write("a = 1")
+	first, err := cache.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cache.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A cached File is the same map, so modifying one shows in the other.
+	first.Set("", "marker", "x")
+	if _, ok := second.Get("", "marker"); !ok {
+		t.Error("expected cached File to be returned")
+	}
+
+	write("a = 22")
+	third, err := cache.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := third.Get("", "a"); v != "22" {
+		t.Errorf("expected changed file to be parsed again, got %q", v)
+	}
+
+	cache.Forget(path)
+	if fourth, _ := cache.Load(path); fourth == nil {
+		t.Error("expected file to load after Forget")
+	}
+}
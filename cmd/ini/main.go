@@ -0,0 +1,58 @@
+// Command ini provides tools for working with INI files.
+//
+// Usage:
+//
+//	ini gen [-pkg name] [-type name] file.ini
+//
+// gen prints a Go source file declaring a struct that matches the layout of
+// the given INI file, with field types inferred from its values.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gonutz/ini"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "gen":
+		gen(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ini gen [-pkg name] [-type name] file.ini")
+	os.Exit(2)
+}
+
+func gen(args []string) {
+	flags := flag.NewFlagSet("gen", flag.ExitOnError)
+	pkg := flags.String("pkg", "main", "package name of the generated file")
+	typeName := flags.String("type", "Config", "name of the generated struct type")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		usage()
+	}
+
+	f, err := ini.Load(flags.Arg(0))
+	check(err)
+	src, err := ini.GenerateStruct(f, *pkg, *typeName)
+	check(err)
+	_, err = os.Stdout.Write(src)
+	check(err)
+}
+
+func check(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ini:", err)
+		os.Exit(1)
+	}
+}
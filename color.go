@@ -0,0 +1,67 @@
+package ini
+
+import (
+	"errors"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// namedColors are the sixteen basic HTML color names.
+var namedColors = map[string]color.RGBA{
+	"black":   {0x00, 0x00, 0x00, 0xFF},
+	"silver":  {0xC0, 0xC0, 0xC0, 0xFF},
+	"gray":    {0x80, 0x80, 0x80, 0xFF},
+	"white":   {0xFF, 0xFF, 0xFF, 0xFF},
+	"maroon":  {0x80, 0x00, 0x00, 0xFF},
+	"red":     {0xFF, 0x00, 0x00, 0xFF},
+	"purple":  {0x80, 0x00, 0x80, 0xFF},
+	"fuchsia": {0xFF, 0x00, 0xFF, 0xFF},
+	"green":   {0x00, 0x80, 0x00, 0xFF},
+	"lime":    {0x00, 0xFF, 0x00, 0xFF},
+	"olive":   {0x80, 0x80, 0x00, 0xFF},
+	"yellow":  {0xFF, 0xFF, 0x00, 0xFF},
+	"navy":    {0x00, 0x00, 0x80, 0xFF},
+	"blue":    {0x00, 0x00, 0xFF, 0xFF},
+	"teal":    {0x00, 0x80, 0x80, 0xFF},
+	"aqua":    {0x00, 0xFF, 0xFF, 0xFF},
+}
+
+// GetColor parses the value for a key in a section as a color. Values may be
+// given as #RRGGBB, #RRGGBBAA or as one of the sixteen basic HTML color names
+// (black, silver, gray, white, maroon, red, purple, fuchsia, green, lime,
+// olive, yellow, navy, blue, teal, aqua). Case is ignored.
+func (f File) GetColor(section, key string) (color.RGBA, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	c, err := parseColor(value)
+	if err != nil {
+		return color.RGBA{}, ErrValue{section, key, value, err}
+	}
+	return c, nil
+}
+
+func parseColor(s string) (color.RGBA, error) {
+	if c, ok := namedColors[strings.ToLower(s)]; ok {
+		return c, nil
+	}
+	if !strings.HasPrefix(s, "#") || (len(s) != 7 && len(s) != 9) {
+		return color.RGBA{}, errors.New("color must be #RRGGBB, #RRGGBBAA or a color name")
+	}
+	hex := s[1:]
+	if len(hex) == 6 {
+		hex += "FF"
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, errors.New("color must be #RRGGBB, #RRGGBBAA or a color name")
+	}
+	return color.RGBA{
+		R: uint8(n >> 24),
+		G: uint8(n >> 16),
+		B: uint8(n >> 8),
+		A: uint8(n),
+	}, nil
+}
@@ -0,0 +1,32 @@
+package ini
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestGetColor(t *testing.T) {
+	file := mustRead(t, `[theme]
+background = #1E1E1E
+overlay = #FF000080
+text = White
+bad = #12345`)
+
+	check := func(key string, expect color.RGBA) {
+		t.Helper()
+		c, err := file.GetColor("theme", key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c != expect {
+			t.Errorf("GetColor(%q): expected %v, got %v", key, expect, c)
+		}
+	}
+	check("background", color.RGBA{0x1E, 0x1E, 0x1E, 0xFF})
+	check("overlay", color.RGBA{0xFF, 0x00, 0x00, 0x80})
+	check("text", color.RGBA{0xFF, 0xFF, 0xFF, 0xFF})
+
+	if _, err := file.GetColor("theme", "bad"); err == nil {
+		t.Error("expected error for malformed color")
+	}
+}
@@ -0,0 +1,123 @@
+package ini
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// LoadUserConfig loads the configuration file fileName of the application
+// appName from the standard configuration directories and merges them, see
+// File.Merge. Files in more specific directories take precedence.
+//
+// The directories are searched according to the XDG Base Directory
+// Specification, $XDG_CONFIG_HOME/appName (defaulting to ~/.config/appName)
+// being the most important, followed by appName in each of $XDG_CONFIG_DIRS
+// (defaulting to /etc/xdg). On macOS ~/Library/Application Support/appName
+// and /Library/Application Support/appName are searched in between.
+//
+// On Windows %APPDATA%\appName is the most important, followed by
+// %PROGRAMDATA%\appName. If fileName exists in the directory of the
+// executable, the application is considered portable and this file takes
+// precedence over all others.
+//
+// Missing files are skipped, if none is found an empty File is returned.
+func LoadUserConfig(appName, fileName string, opts ...Option) (File, error) {
+	f, _, err := LoadUserConfigPath(appName, fileName, opts...)
+	return f, err
+}
+
+// LoadUserConfigPath is like LoadUserConfig but also returns the path that
+// changes to the user's configuration should be saved to. This is the file in
+// the most important directory, or the file next to the executable for
+// portable applications on Windows. The path's directory may not exist yet.
+func LoadUserConfigPath(appName, fileName string, opts ...Option) (f File, writePath string, err error) {
+	var paths []string
+	for _, dir := range configDirs(runtime.GOOS, os.Getenv, userHome()) {
+		paths = append(paths, filepath.Join(dir, appName, fileName))
+	}
+	if len(paths) > 0 {
+		writePath = paths[len(paths)-1]
+	}
+	if runtime.GOOS == "windows" {
+		if exe, err := os.Executable(); err == nil {
+			portable := filepath.Join(filepath.Dir(exe), fileName)
+			if _, err := os.Stat(portable); err == nil {
+				paths = append(paths, portable)
+				writePath = portable
+			}
+		}
+	}
+	f, err = loadMerged(paths, opts)
+	return f, writePath, err
+}
+
+// loadMerged loads all files at paths, which are given in order of increasing
+// precedence, and merges them. Missing files are skipped.
+func loadMerged(paths []string, opts []Option) (File, error) {
+	merged := make(File)
+	for _, path := range paths {
+		f, err := Load(path, opts...)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return merged, err
+		}
+		merged.Merge(f)
+	}
+	return merged, nil
+}
+
+// configDirs returns the base configuration directories in order of increasing
+// precedence.
+func configDirs(goos string, getenv func(string) string, home string) []string {
+	var dirs []string
+	add := func(dir string) {
+		// The XDG specification says to ignore relative paths.
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	if goos == "windows" {
+		for _, env := range []string{"PROGRAMDATA", "APPDATA"} {
+			if dir := getenv(env); dir != "" {
+				dirs = append(dirs, dir)
+			}
+		}
+		return dirs
+	}
+
+	xdgDirs := getenv("XDG_CONFIG_DIRS")
+	if xdgDirs == "" {
+		xdgDirs = "/etc/xdg"
+	}
+	list := filepath.SplitList(xdgDirs)
+	for i := len(list) - 1; i >= 0; i-- {
+		add(list[i])
+	}
+
+	if goos == "darwin" {
+		add("/Library/Application Support")
+		if home != "" {
+			add(filepath.Join(home, "Library", "Application Support"))
+		}
+	}
+
+	if xdgHome := getenv("XDG_CONFIG_HOME"); xdgHome != "" {
+		add(xdgHome)
+	} else if home != "" {
+		add(filepath.Join(home, ".config"))
+	}
+	return dirs
+}
+
+func userHome() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(home)
+}
@@ -0,0 +1,89 @@
+package ini
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestConfigDirs(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+	sep := string(filepath.ListSeparator)
+
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix paths are not absolute on Windows")
+	}
+
+	dirs := configDirs("linux", env(nil), "/home/me")
+	if !reflect.DeepEqual(dirs, []string{"/etc/xdg", "/home/me/.config"}) {
+		t.Errorf("linux defaults: got %v", dirs)
+	}
+
+	dirs = configDirs("linux", env(map[string]string{
+		"XDG_CONFIG_DIRS": "/a" + sep + "relative" + sep + "/b",
+		"XDG_CONFIG_HOME": "/xdg/home",
+	}), "/home/me")
+	if !reflect.DeepEqual(dirs, []string{"/b", "/a", "/xdg/home"}) {
+		t.Errorf("linux with XDG variables: got %v", dirs)
+	}
+
+	dirs = configDirs("darwin", env(nil), "/Users/me")
+	expect := []string{
+		"/etc/xdg",
+		"/Library/Application Support",
+		"/Users/me/Library/Application Support",
+		"/Users/me/.config",
+	}
+	if !reflect.DeepEqual(dirs, expect) {
+		t.Errorf("darwin: got %v", dirs)
+	}
+
+	dirs = configDirs("windows", env(map[string]string{
+		"APPDATA":         `C:\Users\me\AppData\Roaming`,
+		"PROGRAMDATA":     `C:\ProgramData`,
+		"XDG_CONFIG_HOME": "/ignored",
+	}), `C:\Users\me`)
+	if !reflect.DeepEqual(dirs, []string{`C:\ProgramData`, `C:\Users\me\AppData\Roaming`}) {
+		t.Errorf("windows: got %v", dirs)
+	}
+}
+
+func TestLoadUserConfig(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows does not use the XDG variables")
+	}
+	root := t.TempDir()
+	write := func(path, src string) {
+		t.Helper()
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(src), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("system/app/app.ini", "[ui]\ntheme = light\nlang = en")
+	write("user/app/app.ini", "[ui]\ntheme = dark")
+
+	t.Setenv("XDG_CONFIG_DIRS", filepath.Join(root, "system")+string(filepath.ListSeparator)+filepath.Join(root, "none"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "user"))
+	f, writePath, err := LoadUserConfigPath("app", "app.ini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if writePath != filepath.Join(root, "user", "app", "app.ini") {
+		t.Errorf("unexpected write path %q", writePath)
+	}
+	if v, _ := f.Get("ui", "theme"); v != "dark" {
+		t.Errorf("expected user value to win, got %q", v)
+	}
+	if v, _ := f.Get("ui", "lang"); v != "en" {
+		t.Errorf("expected system value to be merged, got %q", v)
+	}
+}
@@ -0,0 +1,37 @@
+package ini
+
+import (
+	"bufio"
+	"io"
+)
+
+// A Decoder reads a File from an input stream. Use Reset to reuse it, and its
+// read buffer, for another input instead of allocating a new one, e.g. with a
+// Decoder per goroutine in a pipeline that parses many small files. A Decoder
+// is not safe for concurrent use.
+type Decoder struct {
+	opts options
+	r    *bufio.Reader
+}
+
+// NewDecoder returns a Decoder that reads from r with the given options.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	d := &Decoder{opts: *makeOptions(opts)}
+	if d.opts.bufferSize > 0 {
+		d.r = bufio.NewReaderSize(r, d.opts.bufferSize)
+	} else {
+		d.r = bufio.NewReader(r)
+	}
+	return d
+}
+
+// Reset discards any buffered data and makes the Decoder read from r.
+func (d *Decoder) Reset(r io.Reader) {
+	d.r.Reset(r)
+}
+
+// Decode reads the rest of the input as a File.
+func (d *Decoder) Decode() (File, error) {
+	o := d.opts
+	return read(d.r, &o)
+}
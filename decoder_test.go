@@ -0,0 +1,36 @@
+package ini
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	d := NewDecoder(strings.NewReader("[s]\nA = 1"), CaseInsensitiveKeys())
+	f, err := d.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f, File{"s": {"a": "1"}}) {
+		t.Errorf("unexpected file %v", f)
+	}
+
+	d.Reset(strings.NewReader("B = 2"))
+	f, err = d.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f, File{"": {"b": "2"}}) {
+		t.Errorf("unexpected file after Reset %v", f)
+	}
+
+	d.Reset(strings.NewReader("invalid"))
+	if _, err := d.Decode(); err != (ErrSyntax{1, "invalid"}) {
+		t.Errorf("expected syntax error, got %v", err)
+	}
+	d.Reset(strings.NewReader("c = 3"))
+	if f, err := d.Decode(); err != nil || f[""]["c"] != "3" {
+		t.Errorf("expected Reset to recover from an error, got %v, %v", f, err)
+	}
+}
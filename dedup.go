@@ -0,0 +1,121 @@
+package ini
+
+import "strings"
+
+// DedupLists removes duplicate entries from the list values separated by sep
+// for which isList returns true, see DedupList, which is useful to clean up a
+// File made by merging several files. Only the caller knows which keys hold
+// lists, a value like "1,000,000" must stay as it is. It returns the number of
+// removed entries.
+func (f File) DedupLists(sep string, isList func(section, key string) bool) (removed int) {
+	for name, s := range f {
+		for key := range s {
+			if isList(name, key) {
+				removed += s.DedupLists(sep, key)
+			}
+		}
+	}
+	return removed
+}
+
+// DedupLists removes duplicate entries from the list values of the given keys,
+// separated by sep, see DedupList. Keys that do not exist are ignored. It
+// returns the number of removed entries.
+func (s Section) DedupLists(sep string, keys ...string) (removed int) {
+	if sep == "" {
+		return 0
+	}
+	for _, key := range keys {
+		value, ok := s[key]
+		if !ok {
+			continue
+		}
+		var n int
+		s[key], n = dedupList(value, sep)
+		removed += n
+	}
+	return removed
+}
+
+// DedupValues removes repeated identical values of keys in all sections, see
+// Section.DedupValues. It returns the number of removed values.
+func (f File) DedupValues() (removed int) {
+	for _, s := range f {
+		removed += s.DedupValues()
+	}
+	return removed
+}
+
+// DedupValues removes repeated identical values of the keys in the section
+// that were read with OnDuplicate(DuplicateCollect), see GetAll. Repeated
+// values often come from reading several files that set the same key to the
+// same value. The last occurrence of each value is kept, so the last value is
+// still the one in the Section. It returns the number of removed values.
+func (s Section) DedupValues() (removed int) {
+	state := stateOf(s, false)
+	if state == nil {
+		return 0
+	}
+	state.Lock()
+	defer state.Unlock()
+	for key, values := range state.values {
+		kept := dedupLast(values)
+		removed += len(values) - len(kept)
+		state.values[key] = kept
+	}
+	return removed
+}
+
+// Dedup removes repeated identical values of a key, like Section.DedupValues,
+// keeping the last occurrence of each. It returns the number of removed
+// values.
+func (m MultiValues) Dedup() (removed int) {
+	for _, keys := range m {
+		for key, values := range keys {
+			kept := dedupLast(values)
+			removed += len(values) - len(kept)
+			keys[key] = kept
+		}
+	}
+	return removed
+}
+
+// dedupLast returns values without repetitions, keeping the last occurrence of
+// each value and their order. It does not modify values.
+func dedupLast(values []string) []string {
+	seen := make(map[string]bool)
+	kept := make([]string, 0, len(values))
+	for i := len(values) - 1; i >= 0; i-- {
+		if !seen[values[i]] {
+			seen[values[i]] = true
+			kept = append(kept, values[i])
+		}
+	}
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	return kept
+}
+
+// DedupList removes duplicate entries from a list value separated by sep,
+// keeping the first occurrence of each. Entries are compared with surrounding
+// white space removed, so "a, b, a" becomes "a, b" for sep ",".
+func DedupList(value, sep string) string {
+	value, _ = dedupList(value, sep)
+	return value
+}
+
+func dedupList(value, sep string) (string, int) {
+	entries := strings.Split(value, sep)
+	kept := entries[:0]
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		trimmed := strings.TrimSpace(e)
+		if seen[trimmed] {
+			continue
+		}
+		seen[trimmed] = true
+		kept = append(kept, e)
+	}
+	return strings.Join(kept, sep), len(entries) - len(kept)
+}
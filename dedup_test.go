@@ -0,0 +1,105 @@
+package ini
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDedupLists(t *testing.T) {
+	f := MustParse(`
+[net]
+allow = 10.0.0.1, 10.0.0.2, 10.0.0.1
+ports = 80
+name = a,b
+count = 1,000,000
+greeting = Hello, world, hello, world`)
+	f.Merge(MustParse("[net]\nname = a,b,,a"))
+
+	isList := func(section, key string) bool {
+		return section == "net" && (key == "allow" || key == "ports" || key == "name")
+	}
+	if n := f.DedupLists(",", isList); n != 2 {
+		t.Errorf("expected 2 removed entries, got %d", n)
+	}
+	expect := File{"net": {
+		"allow":    "10.0.0.1, 10.0.0.2",
+		"ports":    "80",
+		"name":     "a,b,",
+		"count":    "1,000,000",
+		"greeting": "Hello, world, hello, world",
+	}}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %q, got %q", expect, f)
+	}
+	if n := f.Section("net").DedupLists(",", "count", "missing"); n != 1 {
+		t.Errorf("expected 1 removed entry, got %d", n)
+	}
+	if n := f.Section("net").DedupLists("", "greeting"); n != 0 {
+		t.Errorf("expected nothing removed without a separator, got %d", n)
+	}
+}
+
+func TestDedupValues(t *testing.T) {
+	f, err := Read(strings.NewReader("listen = :80\nlisten = :443\nlisten = :80\nx = 1"), OnDuplicate(DuplicateCollect))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := f.DedupValues(); n != 1 {
+		t.Errorf("expected 1 removed value, got %d", n)
+	}
+	if all := f.GetAll("", "listen"); !reflect.DeepEqual(all, []string{":443", ":80"}) {
+		t.Errorf("unexpected values %q", all)
+	}
+	var buf strings.Builder
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "listen = :443\nlisten = :80\nx = 1\n" {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestMultiValuesDedup(t *testing.T) {
+	values := make(MultiValues)
+	var f File
+	for _, src := range []string{"[net]\nports = 80\nports = 443", "[net]\nports = 80\nhost = x"} {
+		next, err := Read(strings.NewReader(src), CollectValues(values))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f == nil {
+			f = next
+		} else {
+			f.Merge(next)
+		}
+	}
+	if n := values.Dedup(); n != 1 {
+		t.Errorf("expected 1 removed value, got %d", n)
+	}
+	expect := MultiValues{"net": {"ports": {"443", "80"}, "host": {"x"}}}
+	if !reflect.DeepEqual(values, expect) {
+		t.Errorf("expected %q, got %q", expect, values)
+	}
+	var buf strings.Builder
+	if err := f.Write(&buf, CollectValues(values)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[net]\nhost = x\nports = 443\nports = 80\n" {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestDedupList(t *testing.T) {
+	tests := []struct{ value, sep, want string }{
+		{"a, b, a", ",", "a, b"},
+		{"a b  a", " ", "a b "},
+		{"", ",", ""},
+		{"x;y;x;y;z", ";", "x;y;z"},
+	}
+	for _, test := range tests {
+		if got := DedupList(test.value, test.sep); got != test.want {
+			t.Errorf("DedupList(%q, %q) = %q, want %q", test.value, test.sep, got, test.want)
+		}
+	}
+}
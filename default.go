@@ -0,0 +1,101 @@
+package ini
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var std = struct {
+	sync.Mutex
+	path   string
+	file   File
+	err    error
+	loaded bool
+}{}
+
+// DefaultPath returns the path that the default File is loaded from. Unless
+// changed with SetDefaultPath, it is the path of the running executable with
+// its extension replaced by .ini, e.g. /usr/local/bin/tool.ini for
+// /usr/local/bin/tool or C:\Tools\tool.ini for C:\Tools\tool.exe.
+func DefaultPath() string {
+	std.Lock()
+	defer std.Unlock()
+	return defaultPath()
+}
+
+func defaultPath() string {
+	if std.path != "" {
+		return std.path
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		exe = os.Args[0]
+	}
+	return strings.TrimSuffix(exe, filepath.Ext(exe)) + ".ini"
+}
+
+// SetDefaultPath sets the path that the default File is loaded from. The file
+// is loaded again on the next call to Default or one of the package-level
+// getters.
+func SetDefaultPath(path string) {
+	std.Lock()
+	defer std.Unlock()
+	std.path = path
+	std.file, std.err, std.loaded = nil, nil, false
+}
+
+// Default returns the default File, loading it from DefaultPath on first use.
+// A missing file is not an error, it results in an empty File. The returned
+// File is shared by all callers.
+func Default() (File, error) {
+	std.Lock()
+	defer std.Unlock()
+	if !std.loaded {
+		std.file, std.err = Load(defaultPath())
+		if os.IsNotExist(std.err) {
+			std.file, std.err = make(File), nil
+		}
+		if std.file == nil {
+			std.file = make(File)
+		}
+		std.loaded = true
+	}
+	return std.file, std.err
+}
+
+// GetString returns the value for a key in a section of the default File, or
+// the empty string if it does not exist or the default File cannot be loaded.
+func GetString(section, key string) string {
+	f, _ := Default()
+	value, _ := f.Get(section, key)
+	return value
+}
+
+// GetInt is like File.GetInt on the default File.
+func GetInt(section, key string, opts ...Option) (int, error) {
+	f, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return f.GetInt(section, key, opts...)
+}
+
+// GetFloat is like File.GetFloat on the default File.
+func GetFloat(section, key string, opts ...Option) (float64, error) {
+	f, err := Default()
+	if err != nil {
+		return 0, err
+	}
+	return f.GetFloat(section, key, opts...)
+}
+
+// GetBool is like File.GetBool on the default File.
+func GetBool(section, key string, opts ...Option) (bool, error) {
+	f, err := Default()
+	if err != nil {
+		return false, err
+	}
+	return f.GetBool(section, key, opts...)
+}
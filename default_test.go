@@ -0,0 +1,40 @@
+package ini
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	defer SetDefaultPath("")
+	if !strings.HasSuffix(DefaultPath(), ".ini") {
+		t.Errorf("unexpected default path %q", DefaultPath())
+	}
+
+	dir := t.TempDir()
+	SetDefaultPath(filepath.Join(dir, "missing.ini"))
+	if f, err := Default(); err != nil || len(f) != 0 {
+		t.Errorf("expected empty File for missing file, got %v, %v", f, err)
+	}
+
+	path := filepath.Join(dir, "app.ini")
+	src := "[server]\nname = main\nport = 8080\ndebug = on\nratio = 0.5"
+	if err := ioutil.WriteFile(path, []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	SetDefaultPath(path)
+	if s := GetString("server", "name"); s != "main" {
+		t.Errorf("GetString: got %q", s)
+	}
+	if n, err := GetInt("server", "port"); err != nil || n != 8080 {
+		t.Errorf("GetInt: got %v, %v", n, err)
+	}
+	if b, err := GetBool("server", "debug"); err != nil || !b {
+		t.Errorf("GetBool: got %v, %v", b, err)
+	}
+	if x, err := GetFloat("server", "ratio"); err != nil || x != 0.5 {
+		t.Errorf("GetFloat: got %v, %v", x, err)
+	}
+}
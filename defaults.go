@@ -0,0 +1,42 @@
+package ini
+
+// SetDefaults registers default values that Get and the typed getters return
+// for keys which the File does not contain. They map section names to keys to
+// values and replace those of an earlier call. The defaults are not part of
+// the File, so ranging over it, SectionNames, Keys and writing only see the
+// explicit values, see IsDefault. The defaults are copied.
+func (f File) SetDefaults(defaults map[string]map[string]string) {
+	copied := make(map[string]map[string]string, len(defaults))
+	for section, keys := range defaults {
+		copied[section] = make(map[string]string, len(keys))
+		for key, value := range keys {
+			copied[section][key] = value
+		}
+	}
+	state := stateOf(f, true)
+	state.Lock()
+	state.defaults = copied
+	state.Unlock()
+}
+
+// IsDefault reports whether the value that Get returns for a key in a section
+// is a default registered with SetDefaults rather than set explicitly.
+func (f File) IsDefault(section, key string) bool {
+	if _, ok := f[section][key]; ok {
+		return false
+	}
+	_, ok := f.defaultValue(section, key)
+	return ok
+}
+
+// defaultValue returns the default for a key in a section, see SetDefaults.
+func (f File) defaultValue(section, key string) (value string, ok bool) {
+	state := stateOf(f, false)
+	if state == nil {
+		return "", false
+	}
+	state.Lock()
+	defer state.Unlock()
+	value, ok = state.defaults[section][key]
+	return value, ok
+}
@@ -0,0 +1,45 @@
+package ini
+
+import "testing"
+
+func TestFileSetDefaults(t *testing.T) {
+	f := MustParse("[net]\ntimeout = 5")
+	defaults := map[string]map[string]string{
+		"net": {"timeout": "30", "retries": "3"},
+	}
+	f.SetDefaults(defaults)
+	defaults["net"]["retries"] = "4"
+
+	if v, _ := f.Get("net", "timeout"); v != "5" {
+		t.Errorf("expected explicit value, got %q", v)
+	}
+	if n, err := f.GetInt("net", "retries"); err != nil || n != 3 {
+		t.Errorf("expected default 3, got %v, %v", n, err)
+	}
+	if _, ok := f.Get("net", "missing"); ok {
+		t.Error("expected no value for a key without a default")
+	}
+	if f.IsDefault("net", "timeout") || !f.IsDefault("net", "retries") || f.IsDefault("net", "missing") {
+		t.Error("wrong IsDefault results")
+	}
+	if _, ok := f["net"]["retries"]; ok {
+		t.Error("defaults must not be added to the File")
+	}
+
+	f.Set("net", "retries", "1")
+	if v, _ := f.Get("net", "retries"); v != "1" || f.IsDefault("net", "retries") {
+		t.Errorf("expected the explicit value to win, got %q", v)
+	}
+
+	f.SetDefaults(map[string]map[string]string{"log": {"level": "info"}})
+	if _, ok := f.Get("net", "timeout"); !ok {
+		t.Error("expected explicit value to remain")
+	}
+	if v, _ := f.Get("log", "level"); v != "info" {
+		t.Errorf("expected new default, got %q", v)
+	}
+	delete(f["net"], "retries")
+	if _, ok := f.Get("net", "retries"); ok {
+		t.Error("expected the old defaults to be replaced")
+	}
+}
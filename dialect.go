@@ -0,0 +1,221 @@
+package ini
+
+// Delimiters sets the characters which separate keys from values. The first
+// delimiter on a line ends the key. The default is "=".
+func Delimiters(chars string) Option {
+	return func(o *options) {
+		o.delimiters = chars
+	}
+}
+
+// CommentPrefixes sets the characters which start a comment line. The default
+// is ";#".
+func CommentPrefixes(chars string) Option {
+	return func(o *options) {
+		o.comments = chars
+	}
+}
+
+func (o *options) delimiterChars() string {
+	if o.delimiters == "" {
+		return "="
+	}
+	return o.delimiters
+}
+
+func (o *options) commentChars() string {
+	if o.comments == "" {
+		return ";#"
+	}
+	return o.comments
+}
+
+// CaseInsensitiveSections converts section names to lower case when reading.
+// Use lower case names to look them up.
+func CaseInsensitiveSections() Option {
+	return func(o *options) {
+		o.lowerSections = true
+	}
+}
+
+// CaseInsensitiveKeys converts keys to lower case when reading. Use lower case
+// keys to look them up.
+func CaseInsensitiveKeys() Option {
+	return func(o *options) {
+		o.lowerKeys = true
+	}
+}
+
+// DuplicatePolicy specifies what happens when a key appears more than once in
+// a section.
+type DuplicatePolicy int
+
+const (
+	// DuplicateOverwrite keeps the last value of a repeated key. This is the
+	// default.
+	DuplicateOverwrite DuplicatePolicy = iota
+	// DuplicateKeepFirst keeps the first value of a repeated key.
+	DuplicateKeepFirst
+	// DuplicateError makes reading fail with an ErrDuplicate.
+	DuplicateError
+	// DuplicateCollect keeps the last value of a repeated key in the File, Get
+	// and the typed getters return it, and all of its values for GetAll.
+	// Writing the File repeats the key for every value.
+	DuplicateCollect
+)
+
+// MultiValues holds all values of the keys of a file, by section and key, in
+// the order in which they appear. See CollectValues.
+type MultiValues map[string]map[string][]string
+
+// Get returns all values of a key in a section, or nil if the key is unknown.
+func (m MultiValues) Get(section, key string) []string {
+	return m[section][key]
+}
+
+func (m MultiValues) add(section, key, value string) {
+	if m[section] == nil {
+		m[section] = make(map[string][]string)
+	}
+	m[section][key] = append(m[section][key], value)
+}
+
+// CollectValues makes reading also keep all values of repeated keys in m, e.g.
+//
+//	listen = :80
+//	listen = :443
+//
+// gives []string{":80", ":443"} for m.Get("", "listen"), as well as for
+// File.GetAll. Reading several files with the same m gathers the values of all
+// of them, for example the documents of a ReadMulti stream. This sets
+// OnDuplicate(DuplicateCollect).
+//
+// Writing with this option repeats a key for every value in m, as long as the
+// last of them is still the value in the File. If the value was changed since,
+// only the new value is written.
+func CollectValues(m MultiValues) Option {
+	return func(o *options) {
+		o.duplicates = DuplicateCollect
+		o.collect = m
+	}
+}
+
+// values returns the values to write for a key of a section, see
+// CollectValues and Section.GetAll.
+func (o *options) values(name, key string, s Section) []string {
+	if all := o.collect.Get(name, key); len(all) > 0 && all[len(all)-1] == s[key] {
+		return all
+	}
+	return s.GetAll(key)
+}
+
+// attachValues attaches the values collected while reading f to its sections
+// for GetAll.
+func attachValues(f File, m MultiValues) {
+	for name, keys := range m {
+		s, ok := f[name]
+		if !ok {
+			continue
+		}
+		state := stateOf(s, true)
+		state.Lock()
+		if state.values == nil {
+			state.values = make(map[string][]string)
+		}
+		for key, values := range keys {
+			state.values[key] = append([]string(nil), values...)
+		}
+		state.Unlock()
+	}
+}
+
+// OnDuplicate sets the policy for keys that appear more than once in a section.
+// Repeated section headers are always allowed, their keys are merged.
+func OnDuplicate(policy DuplicatePolicy) Option {
+	return func(o *options) {
+		o.duplicates = policy
+	}
+}
+
+// ContinuationStyle specifies how values can span multiple lines.
+type ContinuationStyle int
+
+const (
+	// NoContinuation means every line stands for itself. This is the default.
+	NoContinuation ContinuationStyle = iota
+	// IndentContinuation continues a value on every following line that is
+	// indented deeper than the line of its key, as in Python's configparser.
+	// The lines are joined with line breaks. A blank line ends the value.
+	IndentContinuation
+	// BackslashContinuation continues a line that ends in a backslash on the
+	// next line, as in systemd unit files. The lines are joined with a space.
+	BackslashContinuation
+)
+
+// Continuations sets how values can span multiple lines.
+func Continuations(style ContinuationStyle) Option {
+	return func(o *options) {
+		o.continuation = style
+	}
+}
+
+// combine returns an Option that applies all opts.
+func combine(opts ...Option) Option {
+	return func(o *options) {
+		for _, opt := range opts {
+			opt(o)
+		}
+	}
+}
+
+// Dialect presets configure the parser for INI files of well-known programs.
+// They can be combined with further options, later options override earlier
+// ones.
+var (
+	// DialectGit reads Git configuration files: section names and keys are
+	// case-insensitive, keys without values are allowed and lines can be
+	// continued with a backslash.
+	DialectGit = combine(
+		Delimiters("="),
+		CommentPrefixes("#;"),
+		CaseInsensitiveSections(),
+		CaseInsensitiveKeys(),
+		FlagKeys(),
+		OnDuplicate(DuplicateOverwrite),
+		Continuations(BackslashContinuation),
+	)
+
+	// DialectPythonConfigParser reads files like Python's configparser in its
+	// default, strict mode: keys are separated by = or :, keys are
+	// case-insensitive, duplicates are errors and indented lines continue
+	// values.
+	DialectPythonConfigParser = combine(
+		Delimiters("=:"),
+		CommentPrefixes("#;"),
+		CaseInsensitiveKeys(),
+		OnDuplicate(DuplicateError),
+		Continuations(IndentContinuation),
+	)
+
+	// DialectWindowsClassic reads files like the Windows GetPrivateProfile
+	// functions: only ; starts comments, section names and keys are
+	// case-insensitive and the first of repeated keys counts.
+	DialectWindowsClassic = combine(
+		Delimiters("="),
+		CommentPrefixes(";"),
+		CaseInsensitiveSections(),
+		CaseInsensitiveKeys(),
+		OnDuplicate(DuplicateKeepFirst),
+		Continuations(NoContinuation),
+	)
+
+	// DialectSystemd reads systemd unit files: names are case-sensitive, the
+	// last of repeated keys counts and lines can be continued with a
+	// backslash.
+	DialectSystemd = combine(
+		Delimiters("="),
+		CommentPrefixes("#;"),
+		OnDuplicate(DuplicateOverwrite),
+		Continuations(BackslashContinuation),
+	)
+)
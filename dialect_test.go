@@ -0,0 +1,189 @@
+package ini
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDialectOptions(t *testing.T) {
+	check := func(src string, expect File, opts ...Option) {
+		t.Helper()
+		f, err := Read(strings.NewReader(src), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(f, expect) {
+			t.Errorf("expected %q, got %q", expect, f)
+		}
+	}
+
+	check("a: b\nc = d:e", File{"": {"a": "b", "c": "d:e"}}, Delimiters("=:"))
+	check("# not a comment = x\n; comment", File{"": {"# not a comment": "x"}}, CommentPrefixes(";"))
+	check("[Sec]\nKey = Value", File{"sec": {"Key": "Value"}}, CaseInsensitiveSections())
+	check("[Sec]\nKey = Value", File{"Sec": {"key": "Value"}}, CaseInsensitiveKeys())
+	check("a = 1\na = 2", File{"": {"a": "2"}})
+	check("a = 1\na = 2", File{"": {"a": "1"}}, OnDuplicate(DuplicateKeepFirst))
+	check("[s]\na = 1\n[s]\nb = 2", File{"s": {"a": "1", "b": "2"}}, OnDuplicate(DuplicateError))
+
+	_, err := Read(strings.NewReader("[s]\na = 1\nA = 2"), CaseInsensitiveKeys(), OnDuplicate(DuplicateError))
+	if err != (ErrDuplicate{3, "s", "a"}) {
+		t.Errorf("expected ErrDuplicate, got %v", err)
+	}
+}
+
+func TestDuplicateCollect(t *testing.T) {
+	values := make(MultiValues)
+	f, err := Read(strings.NewReader("listen = :80\nlisten = :443\n[s]\nx = 1"), CollectValues(values))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f, File{"": {"listen": ":443"}, "s": {"x": "1"}}) {
+		t.Errorf("expected last values in the File, got %q", f)
+	}
+	if all := values.Get("", "listen"); !reflect.DeepEqual(all, []string{":80", ":443"}) {
+		t.Errorf("unexpected values %q", all)
+	}
+	if all := values.Get("s", "x"); !reflect.DeepEqual(all, []string{"1"}) {
+		t.Errorf("unexpected values %q", all)
+	}
+	if all := values.Get("s", "missing"); all != nil {
+		t.Errorf("expected nil, got %q", all)
+	}
+	if all := values.Get("missing", "x"); all != nil {
+		t.Errorf("expected nil, got %q", all)
+	}
+
+	var buf strings.Builder
+	if err := f.Write(&buf, CollectValues(values)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "listen = :80\nlisten = :443\n\n[s]\nx = 1\n" {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+	f.Set("", "listen", ":8080")
+	buf.Reset()
+	if err := f.Write(&buf, CollectValues(values)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "listen = :8080\n\n[s]\nx = 1\n" {
+		t.Errorf("expected only the changed value, got:\n%s", buf.String())
+	}
+
+	// Values with NUL bytes do not turn into several keys.
+	buf.Reset()
+	if err := (File{"": {"k": "a\x00b"}}).Write(&buf, CollectValues(values)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "k = a\x00b\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+func TestGetAll(t *testing.T) {
+	f, err := Read(strings.NewReader("listen = :80\nlisten = :443\n[s]\nx = 1"), OnDuplicate(DuplicateCollect))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all := f.GetAll("", "listen"); !reflect.DeepEqual(all, []string{":80", ":443"}) {
+		t.Errorf("unexpected values %q", all)
+	}
+	if all := f.Section("s").GetAll("x"); !reflect.DeepEqual(all, []string{"1"}) {
+		t.Errorf("unexpected values %q", all)
+	}
+	if all := f.GetAll("s", "missing"); all != nil {
+		t.Errorf("expected nil, got %q", all)
+	}
+	if all := f.GetAll("missing", "x"); all != nil {
+		t.Errorf("expected nil, got %q", all)
+	}
+	if v, _ := f.Get("", "listen"); v != ":443" {
+		t.Errorf("expected last value, got %q", v)
+	}
+
+	var buf strings.Builder
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "listen = :80\nlisten = :443\n\n[s]\nx = 1\n" {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	merged := File{"": {"listen": ":443", "other": "x"}}
+	merged.Merge(f)
+	if all := merged.GetAll("", "listen"); !reflect.DeepEqual(all, []string{":80", ":443"}) {
+		t.Errorf("unexpected values after Merge %q", all)
+	}
+	merged.Merge(File{"": {"listen": ":443"}})
+	if all := merged.GetAll("", "listen"); !reflect.DeepEqual(all, []string{":443"}) {
+		t.Errorf("expected the merged value only, got %q", all)
+	}
+
+	f.Set("", "listen", ":8080")
+	if all := f.GetAll("", "listen"); !reflect.DeepEqual(all, []string{":8080"}) {
+		t.Errorf("expected the new value only, got %q", all)
+	}
+	if all := (Section{"k": "v"}).GetAll("k"); !reflect.DeepEqual(all, []string{"v"}) {
+		t.Errorf("unexpected values %q", all)
+	}
+}
+
+func TestContinuations(t *testing.T) {
+	check := func(src string, expect File, opts ...Option) {
+		t.Helper()
+		f, err := Read(strings.NewReader(src), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(f, expect) {
+			t.Errorf("expected %q, got %q", expect, f)
+		}
+	}
+
+	indented := "  list = a\n    b\n  # comment\n    c\n  next = x\n\n    after = blank"
+	check(indented, File{"": {"list": "a\nb\nc", "next": "x", "after": "blank"}},
+		Continuations(IndentContinuation))
+	check("a = 1\n  more\na = 2\n  other", File{"": {"a": "1\nmore"}},
+		Continuations(IndentContinuation), OnDuplicate(DuplicateKeepFirst))
+
+	check("cmd = run \\\n  --fast \\\n  --quiet\nx = y\\", File{"": {"cmd": "run --fast --quiet", "x": `y\`}},
+		Continuations(BackslashContinuation))
+	check("cmd = run \\", File{"": {"cmd": `run \`}})
+}
+
+func TestDialectPresets(t *testing.T) {
+	git := MustParse("[Core]\n\tBare = false\n\tautocrlf\n[alias]\n\tlg = log \\\n --graph", DialectGit)
+	expect := File{
+		"core":  {"bare": "false", "autocrlf": ""},
+		"alias": {"lg": "log --graph"},
+	}
+	if !reflect.DeepEqual(git, expect) {
+		t.Errorf("git: expected %q, got %q", expect, git)
+	}
+
+	py := MustParse("[Paths]\nHome: /home\nlist = a\n  b", DialectPythonConfigParser)
+	expect = File{"Paths": {"home": "/home", "list": "a\nb"}}
+	if !reflect.DeepEqual(py, expect) {
+		t.Errorf("python: expected %q, got %q", expect, py)
+	}
+	if _, err := Read(strings.NewReader("a = 1\na = 2"), DialectPythonConfigParser); err == nil {
+		t.Error("python: expected duplicate error")
+	}
+
+	win := MustParse("[Boot]\nShell = explorer.exe\nshell = other.exe\n# = hash", DialectWindowsClassic)
+	expect = File{"boot": {"shell": "explorer.exe", "#": "hash"}}
+	if !reflect.DeepEqual(win, expect) {
+		t.Errorf("windows: expected %q, got %q", expect, win)
+	}
+
+	unit := MustParse("[Service]\nExecStart=/bin/app \\\n  --flag\nRestart=no\nRestart=always", DialectSystemd)
+	expect = File{"Service": {"ExecStart": "/bin/app --flag", "Restart": "always"}}
+	if !reflect.DeepEqual(unit, expect) {
+		t.Errorf("systemd: expected %q, got %q", expect, unit)
+	}
+
+	// Later options override the preset.
+	if f := MustParse("a = 1\na = 2", DialectPythonConfigParser, OnDuplicate(DuplicateOverwrite)); f[""]["a"] != "2" {
+		t.Errorf("expected override of preset, got %q", f)
+	}
+}
@@ -0,0 +1,213 @@
+package ini
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LoadDir loads all files with the extension .ini in dir and merges them, see
+// File.Merge. This is used for drop-in directories like /etc/app/conf.d.
+//
+// Files are merged in order of their priority, given as a numeric prefix of
+// the name like in 10-base.ini and 90-local.ini, values from files of higher
+// priority overriding those of lower priority. Files without a numeric prefix
+// have priority 0. Files of equal priority are merged in lexical order.
+func LoadDir(dir string, opts ...Option) (File, error) {
+	f, _, err := LoadDirOrigins(dir, opts...)
+	return f, err
+}
+
+// Origins record which file each value of a merged File came from. They map
+// section names to keys to file paths.
+type Origins map[string]map[string]string
+
+// Get returns the path of the file that the value for a key in a section came
+// from, or the empty string if the key is unknown.
+func (o Origins) Get(section, key string) string {
+	return o[section][key]
+}
+
+func (o Origins) set(section, key, origin string) {
+	if o[section] == nil {
+		o[section] = make(map[string]string)
+	}
+	o[section][key] = origin
+}
+
+// Origin returns the path of the file that the value for a key in a section
+// was read from, see RecordOrigins. Merge keeps the origins of the keys it
+// copies, so this works for Files from LoadDir and Layers.File as well. It
+// returns the empty string if the key is unknown, was read from an io.Reader
+// or has been changed since.
+func (f File) Origin(section, key string) string {
+	s := f[section]
+	value, ok := s[key]
+	if !ok {
+		return ""
+	}
+	state := stateOf(s, false)
+	if state == nil {
+		return ""
+	}
+	state.Lock()
+	defer state.Unlock()
+	if origin := state.origins[key]; origin.value == value {
+		return origin.path
+	}
+	return ""
+}
+
+// keyOrigin is the origin of a key and the value it had when it was read, see
+// File.Origin.
+type keyOrigin struct {
+	value, path string
+}
+
+// attachOrigins attaches the origins recorded while reading f to its
+// sections for File.Origin.
+func attachOrigins(f File, o Origins) {
+	for name, keys := range o {
+		s, ok := f[name]
+		if !ok {
+			continue
+		}
+		var state *mapState
+		for key, path := range keys {
+			value, ok := s[key]
+			if !ok || path == "" {
+				continue
+			}
+			if state == nil {
+				state = stateOf(s, true)
+				state.Lock()
+				if state.origins == nil {
+					state.origins = make(map[string]keyOrigin)
+				}
+			}
+			state.origins[key] = keyOrigin{value, path}
+		}
+		if state != nil {
+			state.Unlock()
+		}
+	}
+}
+
+// RecordOrigins makes reading record in o which file each value came from.
+// This includes values from files read with Includes. Unlike File.Origin, o
+// can collect the origins of several reads, e.g.
+//
+//	origins := make(ini.Origins)
+//	f, err := ini.Load(path, ini.Includes(), ini.RecordOrigins(origins))
+//	...
+//	fmt.Println("port is set in", origins.Get("server", "port"))
+//
+// Values read from an io.Reader rather than a file have the empty string as
+// their origin. Reading several files with the same Origins records the file
+// that was read last for a key, which is the one whose value wins in a Merge.
+func RecordOrigins(o Origins) Option {
+	return func(opts *options) {
+		opts.origins = o
+	}
+}
+
+// LoadDirOrigins is like LoadDir but also returns which file each value came
+// from, to help find out why a setting has a particular value. See also
+// RecordOrigins.
+func LoadDirOrigins(dir string, opts ...Option) (File, Origins, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.ini"))
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		a, b := filepath.Base(paths[i]), filepath.Base(paths[j])
+		if pa, pb := dropInPriority(a), dropInPriority(b); pa != pb {
+			return pa < pb
+		}
+		return a < b
+	})
+
+	merged := make(File)
+	origins := make(Origins)
+	opts = append(opts[:len(opts):len(opts)], RecordOrigins(origins), sharedBudget())
+	for _, path := range paths {
+		f, err := Load(path, opts...)
+		if err != nil {
+			return merged, origins, err
+		}
+		merged.Merge(f)
+	}
+	return merged, origins, nil
+}
+
+// dropInPriority returns the numeric prefix of a file name like 10-base.ini,
+// or 0 if it has none.
+func dropInPriority(name string) int {
+	digits := 0
+	for digits < len(name) && '0' <= name[digits] && name[digits] <= '9' {
+		digits++
+	}
+	if digits == 0 || digits == len(name) || name[digits] != '-' {
+		return 0
+	}
+	n, err := strconv.Atoi(name[:digits])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// SplitDir writes every section of the File to its own file in dir, which is
+// created if necessary. This is the inverse of LoadDir. The function naming
+// returns the file name for a section; sections with the same file name are
+// written to the same file. If naming is nil, DefaultSplitName is used.
+func (f File) SplitDir(dir string, naming func(section string) string, opts ...Option) error {
+	if naming == nil {
+		naming = DefaultSplitName
+	}
+	files := make(map[string]File)
+	for name, section := range f {
+		if name == "" && len(section) == 0 {
+			continue
+		}
+		fileName := naming(name)
+		if files[fileName] == nil {
+			files[fileName] = make(File)
+		}
+		files[fileName][name] = section
+	}
+
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	for fileName, part := range files {
+		if err := part.Save(filepath.Join(dir, fileName), opts...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DefaultSplitName is the default file naming for File.SplitDir. It returns the
+// section name with the extension .ini, replacing all characters other than
+// ASCII letters, digits, dots, dashes and underscores with underscores. The
+// default section is written to _default.ini.
+func DefaultSplitName(section string) string {
+	if section == "" {
+		return "_default.ini"
+	}
+	name := strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' ||
+			r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, section)
+	if strings.Trim(name, ".") == "" {
+		// Do not produce the names . and .. or hidden files.
+		name = "_" + name
+	}
+	return name + ".ini"
+}
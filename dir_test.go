@@ -0,0 +1,186 @@
+package ini
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSplitAndLoadDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "conf.d")
+	f := MustParse("top = 1\n[server]\nport = 80\n[remote \"origin\"]\nurl = x\n[empty]")
+	if err := f.SplitDir(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	sort.Strings(names)
+	expect := []string{"_default.ini", "empty.ini", "remote__origin_.ini", "server.ini"}
+	if !reflect.DeepEqual(names, expect) {
+		t.Errorf("expected files %v, got %v", expect, names)
+	}
+
+	back, err := LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, f) {
+		t.Errorf("expected %v, got %v", f, back)
+	}
+}
+
+func TestSplitDirNaming(t *testing.T) {
+	dir := t.TempDir()
+	f := MustParse("[a]\nx = 1\n[b]\ny = 2")
+	err := f.SplitDir(dir, func(string) string { return "all.ini" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all, err := Load(filepath.Join(dir, "all.ini")); err != nil || !reflect.DeepEqual(all, f) {
+		t.Errorf("expected all sections in one file, got %v, %v", all, err)
+	}
+}
+
+func TestLoadDirOrder(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"a.ini":      "x = a\ny = a",
+		"b.ini":      "x = b",
+		"ignore.txt": "x = ignored",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f, File{"": {"x": "b", "y": "a"}}) {
+		t.Errorf("unexpected merge result %v", f)
+	}
+}
+
+func TestLoadDirPriorities(t *testing.T) {
+	dir := t.TempDir()
+	for name, src := range map[string]string{
+		"90-local.ini":  "[db]\nhost = local",
+		"10-base.ini":   "[db]\nhost = base\nport = 5432\nuser = app",
+		"5-early.ini":   "[db]\nuser = early",
+		"plain.ini":     "[db]\nuser = plain\nname = plain",
+		"100-final.ini": "[db]\nport = 6432",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, origins, err := LoadDirOrigins(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := File{"db": {"host": "local", "port": "6432", "user": "app", "name": "plain"}}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %v, got %v", expect, f)
+	}
+	for key, file := range map[string]string{
+		"host": "90-local.ini",
+		"port": "100-final.ini",
+		"user": "10-base.ini",
+		"name": "plain.ini",
+	} {
+		if got := origins.Get("db", key); got != filepath.Join(dir, file) {
+			t.Errorf("%s: expected origin %s, got %s", key, file, got)
+		}
+		if got := f.Origin("db", key); got != filepath.Join(dir, file) {
+			t.Errorf("%s: expected File.Origin %s, got %s", key, file, got)
+		}
+	}
+	if got := origins.Get("db", "missing"); got != "" {
+		t.Errorf("expected no origin for missing key, got %q", got)
+	}
+}
+
+func TestDefaultSplitName(t *testing.T) {
+	for section, expect := range map[string]string{
+		"":           "_default.ini",
+		"server":     "server.ini",
+		"a/b\\c":     "a_b_c.ini",
+		"..":         "_...ini",
+		"v1.2-beta_": "v1.2-beta_.ini",
+	} {
+		if got := DefaultSplitName(section); got != expect {
+			t.Errorf("%q: expected %q, got %q", section, expect, got)
+		}
+	}
+}
+
+func TestRecordOrigins(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.ini":  "a = 1\ninclude = extra.ini\n[s]\nc = 3",
+		"extra.ini": "b = 2\n[s]\nc = 4\nd = 5",
+	})
+	main, extra := filepath.Join(dir, "main.ini"), filepath.Join(dir, "extra.ini")
+
+	origins := make(Origins)
+	if _, err := Load(main, Includes(), RecordOrigins(origins)); err != nil {
+		t.Fatal(err)
+	}
+	expect := Origins{
+		"":  {"a": main, "b": extra},
+		"s": {"c": main, "d": extra},
+	}
+	if !reflect.DeepEqual(origins, expect) {
+		t.Errorf("expected %v, got %v", expect, origins)
+	}
+
+	origins = make(Origins)
+	if _, err := Load(main, Includes(), RecordOrigins(origins), OnDuplicate(DuplicateKeepFirst)); err != nil {
+		t.Fatal(err)
+	}
+	if got := origins.Get("s", "c"); got != extra {
+		t.Errorf("expected first value's origin %q, got %q", extra, got)
+	}
+}
+
+func TestFileOrigin(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.ini":  "a = 1\ninclude = extra.ini\n[s]\nc = 3",
+		"extra.ini": "b = 2",
+	})
+	main, extra := filepath.Join(dir, "main.ini"), filepath.Join(dir, "extra.ini")
+
+	f, err := Load(main, Includes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct{ section, key, origin string }{
+		{"", "a", main},
+		{"", "b", extra},
+		{"s", "c", main},
+		{"s", "missing", ""},
+	} {
+		if got := f.Origin(c.section, c.key); got != c.origin {
+			t.Errorf("%s: expected origin %q, got %q", c.key, c.origin, got)
+		}
+	}
+
+	f.Set("s", "c", "changed")
+	if got := f.Origin("s", "c"); got != "" {
+		t.Errorf("a changed value must have no origin, got %q", got)
+	}
+	if got := MustParse("a = 1").Origin("", "a"); got != "" {
+		t.Errorf("values from an io.Reader must have no origin, got %q", got)
+	}
+}
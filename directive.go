@@ -0,0 +1,119 @@
+package ini
+
+import "strings"
+
+// Directives are comments that tell tools like Lint and Format how to treat
+// the lines around them, in the way linters for Go source code do it:
+//
+//	; ini:lint-disable trailing-whitespace
+//	; ini:lint-enable trailing-whitespace
+//	; ini:lint-disable-next-line
+//	; ini:keep
+//
+// lint-disable turns off the named Lint rules, or all rules if none are named,
+// until the end of the file or a matching lint-enable. lint-disable-next-line
+// does the same for the line after it only. keep makes Format leave the next
+// line exactly as it is.
+const (
+	DirectiveLintDisable         = "lint-disable"
+	DirectiveLintEnable          = "lint-enable"
+	DirectiveLintDisableNextLine = "lint-disable-next-line"
+	DirectiveKeep                = "keep"
+)
+
+// directivePrefix starts the text of a directive comment.
+const directivePrefix = "ini:"
+
+// Directive returns the name and arguments of a directive comment like
+// "; ini:lint-disable trailing-whitespace". ok is false for nodes that are not
+// directive comments.
+func (n Node) Directive() (name string, args []string, ok bool) {
+	if n.Kind != CommentNode {
+		return "", nil, false
+	}
+	return parseDirective(n.Value)
+}
+
+// parseDirective parses the text of a comment after the comment character.
+func parseDirective(text string) (name string, args []string, ok bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, directivePrefix) {
+		return "", nil, false
+	}
+	fields := strings.Fields(text[len(directivePrefix):])
+	if len(fields) == 0 {
+		return "", nil, false
+	}
+	return fields[0], fields[1:], true
+}
+
+// lineDirective returns the directive on a raw line of a file, see
+// parseDirective. Lint and Format take no options, so only the default comment
+// characters are recognized.
+func lineDirective(line string) (name string, args []string, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.IndexByte(";#", line[0]) == -1 {
+		return "", nil, false
+	}
+	return parseDirective(line[1:])
+}
+
+// lintSuppression keeps track of the directives while going through the lines
+// of a file, see advance.
+type lintSuppression struct {
+	all   bool            // all rules are disabled, unless rules says otherwise
+	rules map[string]bool // rules explicitly disabled or enabled
+
+	// The line fields apply to the current line, the next fields to the one
+	// after it.
+	lineAll, nextAll     bool
+	lineRules, nextRules map[string]bool
+	lineKeep, nextKeep   bool
+}
+
+// advance must be called with every line before checking it.
+func (s *lintSuppression) advance(line string) {
+	s.lineAll, s.lineRules, s.lineKeep = s.nextAll, s.nextRules, s.nextKeep
+	s.nextAll, s.nextRules, s.nextKeep = false, nil, false
+	name, args, ok := lineDirective(line)
+	if !ok {
+		return
+	}
+	switch name {
+	case DirectiveLintDisable, DirectiveLintEnable:
+		disable := name == DirectiveLintDisable
+		if len(args) == 0 {
+			s.all, s.rules = disable, nil
+		}
+		for _, rule := range args {
+			if s.rules == nil {
+				s.rules = make(map[string]bool)
+			}
+			s.rules[rule] = disable
+		}
+	case DirectiveLintDisableNextLine:
+		s.nextAll = len(args) == 0
+		s.nextRules = make(map[string]bool)
+		for _, rule := range args {
+			s.nextRules[rule] = true
+		}
+	case DirectiveKeep:
+		s.nextKeep = true
+	}
+}
+
+// disabled reports whether rule is turned off for the current line.
+func (s *lintSuppression) disabled(rule string) bool {
+	if s.lineAll || s.lineRules[rule] {
+		return true
+	}
+	if disabled, ok := s.rules[rule]; ok {
+		return disabled
+	}
+	return s.all
+}
+
+// keep reports whether Format must leave the current line unchanged.
+func (s *lintSuppression) keep() bool {
+	return s.lineKeep
+}
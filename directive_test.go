@@ -0,0 +1,62 @@
+package ini
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDirectives(t *testing.T) {
+	src := "a = 1 \n" +
+		"; ini:lint-disable-next-line\n" +
+		"b = 2 \n" +
+		"c = 3 \n" +
+		"# ini:lint-disable trailing-whitespace\n" +
+		"d = 4 \n" +
+		"e = a\u00A0b\n" +
+		"; ini:lint-enable trailing-whitespace\n" +
+		"; ini:keep\n" +
+		"f = 6 \n" +
+		"; ini:lint-disable\n" +
+		"; ini:lint-enable non-breaking-space\n" +
+		"g = a\u00A0b \n"
+	issues, err := Lint(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []LintIssue{
+		{1, LintTrailingWhitespace, "trailing whitespace"},
+		{4, LintTrailingWhitespace, "trailing whitespace"},
+		{7, LintNonBreakingSpace, "non-breaking space"},
+		{10, LintTrailingWhitespace, "trailing whitespace"},
+		{13, LintNonBreakingSpace, "non-breaking space"},
+	}
+	if !reflect.DeepEqual(issues, expect) {
+		t.Errorf("expected %v, got %v", expect, issues)
+	}
+
+	var buf bytes.Buffer
+	if err := Format(strings.NewReader("a = 1 \n; ini:keep\nb = 2 \nc = 3 \n"), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if want := "a = 1\n; ini:keep\nb = 2 \nc = 3\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestNodeDirective(t *testing.T) {
+	doc, err := ParseDocument(strings.NewReader("; ini:lint-disable a b\n; plain\nk = ; ini:keep"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	name, args, ok := doc.Nodes[0].Directive()
+	if !ok || name != DirectiveLintDisable || !reflect.DeepEqual(args, []string{"a", "b"}) {
+		t.Errorf("unexpected directive %q %q %v", name, args, ok)
+	}
+	for _, n := range doc.Nodes[1:] {
+		if _, _, ok := n.Directive(); ok {
+			t.Errorf("%q is not a directive", n.Raw)
+		}
+	}
+}
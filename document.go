@@ -0,0 +1,311 @@
+package ini
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NodeKind is the kind of a line in a Document.
+type NodeKind int
+
+const (
+	BlankNode   NodeKind = iota // an empty line or one of only whitespace
+	CommentNode                 // a line starting with a comment prefix
+	SectionNode                 // a section header
+	KeyNode                     // a key and its value
+)
+
+// A Node is a line of a Document, or several lines for values that were
+// continued, see Continuations.
+type Node struct {
+	Kind NodeKind
+	Line int // the number of the first line, 0 for nodes added to a Document
+
+	// Raw is the original text without the final line break. Continued lines
+	// are separated by \n, regardless of the line breaks in the file. It is
+	// written back unchanged unless the node is modified.
+	Raw string
+
+	// Section is the name of a section node or the section that a node is
+	// in, Key and Value are the key and value of a key node. Value is the
+	// text after the comment prefix for comment nodes.
+	Section string
+	Key     string
+	Value   string
+
+	// Quote is the quote character around the value, '"' or '\'', for key
+	// nodes read with QuotedValues, or 0 for values without quotes.
+	Quote byte
+
+	// valueStart and valueEnd are the position of the value as written in
+	// the first line of Raw, valueStart is 0 if that is not known.
+	valueStart, valueEnd int
+}
+
+// A Document is an INI file parsed for editing. Unlike a File, it keeps
+// everything needed to write the file back the way it was: comments, blank
+// lines, the order of keys, their original formatting, line breaks and whether
+// the file ended in a line break. Only the lines of modified keys change.
+type Document struct {
+	Nodes        []Node
+	FinalNewline bool   // the last line ends in a line break
+	LineBreak    string // "\n" or "\r\n"
+	BOM          bool   // the file starts with a UTF-8 byte order mark
+
+	// Includes are the include directives that were resolved when reading
+	// with the Includes option, including those in included files, in the
+	// order they were read. Together they form the include graph.
+	Includes []Include
+
+	opts *options
+	file File // the values of the Document, see File
+}
+
+// ParseDocument reads a Document from r. The options are the same as for Read.
+func ParseDocument(r io.Reader, opts ...Option) (*Document, error) {
+	return parseDocument(r, makeOptions(opts))
+}
+
+// LoadDocument reads a Document from a file on disk.
+func LoadDocument(path string, opts ...Option) (*Document, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	o := makeOptions(opts)
+	o.baseDir = filepath.Dir(path)
+	o.source = path
+	return parseDocument(f, o)
+}
+
+func parseDocument(r io.Reader, opts *options) (*Document, error) {
+	d := &Document{LineBreak: "\n", opts: opts}
+	// The nodes are also added to a File to report the same errors as Read.
+	fileOpts := opts.fileOptions()
+	b := newFileBuilder(make(File), fileOpts)
+	b.includes = &d.Includes
+	r, err := b.opts.limitReader(r, opts.source)
+	if err != nil {
+		return nil, err
+	}
+	r, err = opts.unseal(r)
+	if err != nil {
+		return nil, err
+	}
+	info, err := scan(bufio.NewReader(r), b.opts, func(n Node) error {
+		d.Nodes = append(d.Nodes, n)
+		return b.add(n)
+	})
+	if err == nil {
+		err = finishFile(b.file, fileOpts, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	d.file = b.file
+	d.FinalNewline = info.finalNewline
+	d.BOM = info.bom
+	if info.crlf {
+		d.LineBreak = "\r\n"
+	}
+	return d, nil
+}
+
+// File returns the contents of the Document as a File, the same as Read gives
+// for the file, including the values of included files, with the changes made
+// by Set and Delete. Changes to Nodes are not reflected. The File is a copy,
+// changing it does not change the Document.
+func (d *Document) File() File {
+	f := make(File)
+	f.Merge(d.file)
+	return f
+}
+
+func (d *Document) options() *options {
+	if d.opts == nil {
+		d.opts = &options{}
+	}
+	return d.opts
+}
+
+// Get looks up a value for a key in a section in the File of the Document,
+// see File, so it sees included values but not changes to Nodes.
+func (d *Document) Get(section, key string) (value string, ok bool) {
+	return d.file.Get(section, key)
+}
+
+// keyIndex returns the index of the node that determines the value for a key
+// in a section, or -1 if there is none.
+func (d *Document) keyIndex(section, key string) int {
+	index := -1
+	for i, n := range d.Nodes {
+		if n.Kind == KeyNode && n.Section == section && n.Key == key {
+			index = i
+			if d.options().duplicates == DuplicateKeepFirst {
+				break
+			}
+		}
+	}
+	return index
+}
+
+// Set stores a value for a key in a section. An existing key keeps its
+// formatting, only the value changes. A new key is added after the last key of
+// the section, so comments at the end of a section stay there. A new section
+// is added at the end of the Document. The value is escaped like File.Write
+// does for the options of the Document. Values with line breaks are written as
+// continued lines, which needs IndentContinuation. Set returns an
+// ErrNotWritable and leaves the Document unchanged for keys and values that
+// would be read back differently, see File.Write.
+func (d *Document) Set(section, key, value string) error {
+	if err := d.options().checkWritable(section, key, value); err != nil {
+		return err
+	}
+	if d.file == nil {
+		d.file = make(File)
+	}
+	d.file.Section(section)[key] = value
+	if i := d.keyIndex(section, key); i != -1 {
+		d.setValue(&d.Nodes[i], value)
+		return nil
+	}
+
+	n := Node{Kind: KeyNode, Section: section, Key: key}
+	// Insert after the last key or the header of the section.
+	insert := -1
+	for i, existing := range d.Nodes {
+		if existing.Section != section {
+			continue
+		}
+		if existing.Kind == KeyNode || existing.Kind == SectionNode {
+			insert = i + 1
+		}
+	}
+	if insert == -1 && section == "" {
+		// The default section has no header, it comes before the first one.
+		insert = len(d.Nodes)
+		for i, existing := range d.Nodes {
+			if existing.Kind == SectionNode {
+				insert = i
+				break
+			}
+		}
+	} else if insert == -1 {
+		if len(d.Nodes) > 0 {
+			d.Nodes = append(d.Nodes, Node{Kind: BlankNode, Section: d.Nodes[len(d.Nodes)-1].Section})
+		}
+		header := "[" + d.options().escape(section) + "]"
+		d.Nodes = append(d.Nodes, Node{Kind: SectionNode, Section: section, Raw: header})
+		insert = len(d.Nodes)
+	} else if d.Nodes[insert-1].Kind == KeyNode {
+		// Indent like the previous key.
+		prev := d.Nodes[insert-1].Raw
+		n.Raw = prev[:len(prev)-len(strings.TrimLeft(prev, " \t"))]
+	}
+	d.setValue(&n, value)
+	d.insert(insert, n)
+	return nil
+}
+
+// setValue changes the value of a key node. The value is written in the
+// quotes it had before, or in new ones if it needs them with QuotedValues.
+func (d *Document) setValue(n *Node, value string) {
+	o := d.options()
+	text, quote := o.encodeValue(value), byte(0)
+	if !strings.Contains(value, "\n") && (n.Quote != 0 || o.quotes && needsQuotes(value)) {
+		quoted := quoteValue(value, n.Quote)
+		text, quote = o.encodeValue(quoted), quoted[0]
+	}
+	d.setRawValue(n, text)
+	n.Value, n.Quote = value, quote
+}
+
+func (d *Document) insert(i int, n Node) {
+	d.Nodes = append(d.Nodes, Node{})
+	copy(d.Nodes[i+1:], d.Nodes[i:])
+	d.Nodes[i] = n
+}
+
+// Delete removes all lines for a key in a section and reports whether there
+// were any. The key is also removed from File and Get, even if an included
+// file has a value for it, which it gets back when the Document is read
+// again.
+func (d *Document) Delete(section, key string) bool {
+	kept := d.Nodes[:0]
+	for _, n := range d.Nodes {
+		if n.Kind != KeyNode || n.Section != section || n.Key != key {
+			kept = append(kept, n)
+		}
+	}
+	deleted := len(kept) != len(d.Nodes)
+	d.Nodes = kept
+	delete(d.file[section], key)
+	return deleted
+}
+
+// setRawValue replaces the value in the raw text of a key node with text,
+// keeping indentation and the spacing around the delimiter. The position of
+// the value is known for key lines that were read, other nodes get a new key
+// line.
+func (d *Document) setRawValue(n *Node, text string) {
+	var prefix, suffix string
+	if n.valueStart > 0 && n.valueEnd > n.valueStart {
+		prefix = n.Raw[:n.valueStart]
+		if !strings.Contains(n.Raw, "\n") {
+			// Continued lines are part of the value and replaced as well.
+			suffix = n.Raw[n.valueEnd:]
+		}
+	} else {
+		indent := n.Raw[:len(n.Raw)-len(strings.TrimLeft(n.Raw, " \t"))]
+		prefix = indent + d.options().keyPrefix(d.options().escape(n.Key))
+	}
+	if text == "" {
+		// Without a value there is nothing to keep the spacing for.
+		n.Raw = strings.TrimRight(prefix, " \t") + suffix
+		n.valueStart, n.valueEnd = 0, 0
+		return
+	}
+	text = continueLines(text)
+	n.Raw = prefix + text + suffix
+	n.valueStart, n.valueEnd = len(prefix), len(prefix)+len(text)
+}
+
+// WriteTo writes the Document to w. It is encrypted or signed if the
+// Document was read with Encrypted or Signed.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	lineBreak := d.LineBreak
+	if lineBreak == "" {
+		lineBreak = "\n"
+	}
+	var buf bytes.Buffer
+	if d.BOM {
+		buf.WriteString(utf8BOM)
+	}
+	for i, n := range d.Nodes {
+		buf.WriteString(strings.Replace(n.Raw, "\n", lineBreak, -1))
+		if i < len(d.Nodes)-1 || d.FinalNewline {
+			buf.WriteString(lineBreak)
+		}
+	}
+	data, err := d.options().seal(buf.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// Save writes the Document to a file on disk.
+func (d *Document) Save(path string) error {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0666)
+}
@@ -0,0 +1,158 @@
+package ini
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocumentRoundTrip(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"a = b",
+		"a = b\n",
+		"; header\r\n\r\n[s]\r\n  key=value   \r\n; trailing\r\n",
+		"[s]\nx = 1\n\n# end of file comment\n# and another",
+		"list = a\n  b\n  # inner\n  c\n",
+		"\xEF\xBB\xBF[s]\r\nx = 1\r\n",
+	} {
+		d, err := ParseDocument(strings.NewReader(src), Continuations(IndentContinuation))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if _, err := d.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != src {
+			t.Errorf("expected %q, got %q", src, buf.String())
+		}
+		f, _ := Read(strings.NewReader(src), Continuations(IndentContinuation))
+		if !reflect.DeepEqual(d.File(), f) {
+			t.Errorf("expected File %v, got %v", f, d.File())
+		}
+	}
+}
+
+func TestDocumentEdit(t *testing.T) {
+	src := "top = 1\n" +
+		"[server]\n" +
+		"  host=localhost  ; not a comment\n" +
+		"  port = 80\n" +
+		"\n" +
+		"; comment at the end of server\n" +
+		"[client]\n" +
+		"retries = 3\n" +
+		"\n" +
+		"# trailing file comment"
+	d, err := ParseDocument(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set("server", "port", "8080")
+	d.Set("server", "tls", "on")
+	d.Set("client", "retries", "5")
+	d.Set("", "new", "x")
+	d.Set("logging", "level", "debug")
+	if !d.Delete("server", "host") {
+		t.Error("expected host to be deleted")
+	}
+	if d.Delete("server", "host") {
+		t.Error("expected nothing to be deleted the second time")
+	}
+
+	expect := "top = 1\n" +
+		"new = x\n" +
+		"[server]\n" +
+		"  port = 8080\n" +
+		"  tls = on\n" +
+		"\n" +
+		"; comment at the end of server\n" +
+		"[client]\n" +
+		"retries = 5\n" +
+		"\n" +
+		"# trailing file comment\n" +
+		"\n" +
+		"[logging]\n" +
+		"level = debug"
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, buf.String())
+	}
+	if v, ok := d.Get("server", "port"); !ok || v != "8080" {
+		t.Errorf("Get: got %q, %v", v, ok)
+	}
+	if _, err := ParseDocument(strings.NewReader("a = 1\na = 2"), OnDuplicate(DuplicateError)); err == nil {
+		t.Error("expected duplicate error")
+	}
+}
+
+func TestDocumentFileKeepsCallerState(t *testing.T) {
+	values := make(MultiValues)
+	var order Order
+	d, err := ParseDocument(strings.NewReader("a = 1\na = 2\n"), CollectValues(values), KeepOrder(&order))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if all := d.File().GetAll("", "a"); !reflect.DeepEqual(all, []string{"1", "2"}) {
+			t.Errorf("unexpected values in File %q", all)
+		}
+	}
+	if all := values.Get("", "a"); !reflect.DeepEqual(all, []string{"1", "2"}) {
+		t.Errorf("values were collected again: %q", all)
+	}
+
+	d.Set("", "b", "3")
+	d.Delete("", "a")
+	if f := d.File(); !reflect.DeepEqual(f, File{"": {"b": "3"}}) {
+		t.Errorf("File does not follow Set and Delete: %q", f)
+	}
+	d.File()[""]["b"] = "changed"
+	if v, _ := d.Get("", "b"); v != "3" {
+		t.Errorf("changing the File changed the Document: %q", v)
+	}
+}
+
+func TestDocumentSetEncodesValues(t *testing.T) {
+	tests := []struct {
+		src, section, key, value string
+		opts                     []Option
+		expect                   string
+	}{
+		{"A = \\u0041\n", "", "A", "B", []Option{UnicodeEscapes()}, "A = B\n"},
+		{"a  =  old\n", "", "a", "${missing}", []Option{Interpolation(DollarInterpolation)}, "a  =  $${missing}\n"},
+		{"", "", "a", "100%", []Option{Interpolation(PercentInterpolation)}, "a = 100%%\n"},
+		{"a = x\n", "", "a", "ü", []Option{EscapeNonASCII()}, "a = \\u00FC\n"},
+		{"", "ä", "ö", "ü", []Option{EscapeNonASCII()}, "[\\u00E4]\n\\u00F6 = \\u00FC\n"},
+		{"a = 'x'\n", "", "a", "${y}", []Option{QuotedValues(), Interpolation(DollarInterpolation)}, "a = '$${y}'\n"},
+		{"a =\n", "", "a", "x", nil, "a = x\n"},
+		{"a = x\n", "", "a", "", nil, "a =\n"},
+	}
+	for _, test := range tests {
+		d, err := ParseDocument(strings.NewReader(test.src), test.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.FinalNewline = true
+		if err := d.Set(test.section, test.key, test.value); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if _, err := d.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != test.expect {
+			t.Errorf("%q: expected %q, got %q", test.src, test.expect, buf.String())
+		}
+		f, err := Read(strings.NewReader(buf.String()), append(test.opts, UnicodeEscapes())...)
+		if err != nil {
+			t.Errorf("%q: reading the output: %v", test.src, err)
+		}
+		if v, _ := f.Get(test.section, test.key); v != test.value {
+			t.Errorf("%q: read back %q instead of %q", test.src, v, test.value)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package ini
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RedactionRules decide which values File.SupportDump hides.
+type RedactionRules struct {
+	// Keys are redacted if their names contain one of these strings, case is
+	// ignored. If nil, DefaultRedactedKeys are used.
+	KeyPatterns []string
+
+	// Keys are also redacted if one of the words of their names is one of
+	// these, case is ignored. Words are separated by characters other than
+	// letters and digits and by upper case letters after lower case ones, so
+	// db_pass, api-key and authToken all contain a word of the defaults. If
+	// nil, DefaultRedactedWords are used.
+	KeyWords []string
+
+	// Redact, if not nil, is called for values that the KeyPatterns do not
+	// redact and hides them if it returns true.
+	Redact func(section, key, value string) bool
+
+	// Replacement is written instead of redacted values, "<redacted>" if it
+	// is empty.
+	Replacement string
+
+	// Origins, if not nil, are written as comments before the keys, see
+	// AnnotateOrigins.
+	Origins Origins
+}
+
+// DefaultRedactedKeys are the KeyPatterns used if RedactionRules do not
+// specify any.
+var DefaultRedactedKeys = []string{
+	"password", "passwd", "secret", "token", "apikey", "api_key", "private", "credential",
+}
+
+// DefaultRedactedWords are the KeyWords used if RedactionRules do not specify
+// any.
+var DefaultRedactedWords = []string{
+	"pass", "pwd", "key", "auth", "authorization",
+}
+
+func (r RedactionRules) redacts(section, key, value string) bool {
+	patterns := r.KeyPatterns
+	if patterns == nil {
+		patterns = DefaultRedactedKeys
+	}
+	lower := strings.ToLower(key)
+	for _, p := range patterns {
+		if strings.Contains(lower, strings.ToLower(p)) {
+			return true
+		}
+	}
+	words := r.KeyWords
+	if words == nil {
+		words = DefaultRedactedWords
+	}
+	for _, w := range keyWords(key) {
+		for _, redacted := range words {
+			if strings.EqualFold(w, redacted) {
+				return true
+			}
+		}
+	}
+	return r.Redact != nil && r.Redact(section, key, value)
+}
+
+// keyWords splits a key name into words, see RedactionRules.KeyWords.
+func keyWords(key string) []string {
+	var words []string
+	var word []rune
+	var prev rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+	for _, c := range key {
+		switch {
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			flush()
+		case unicode.IsUpper(c) && unicode.IsLower(prev):
+			flush()
+			word = append(word, c)
+		default:
+			word = append(word, c)
+		}
+		prev = c
+	}
+	flush()
+	return words
+}
+
+// apply returns a copy of f with the values replaced that the rules redact.
+func (r RedactionRules) apply(f File) File {
+	replacement := r.Replacement
+	if replacement == "" {
+		replacement = "<redacted>"
+	}
+	sanitized := make(File)
+	for name, section := range f {
+		s := sanitized.Section(name)
+		for key, value := range section {
+			if value != "" && r.redacts(name, key, value) {
+				value = replacement
+			}
+			s[key] = value
+		}
+	}
+	return sanitized
+}
+
+// SupportDump writes the File in INI format with secret values replaced, to
+// be attached to bug reports. Pass the merged File and the Origins of Layers,
+// see Layers.File and Layers.Origins, to include defaults and where every
+// value came from. Empty values are not redacted so that the dump shows that
+// they are empty. Values with line breaks are written as continued lines, see
+// IndentContinuation, values that can not be written like that, e.g. because
+// of blank lines, are written as Go string literals. Keys are written as they
+// are, even if they could not be read back.
+func (f File) SupportDump(w io.Writer, redact RedactionRules) error {
+	sanitized := redact.apply(f)
+	opts := &options{annotate: redact.Origins, continuation: IndentContinuation, anyKeys: true}
+	for _, section := range sanitized {
+		for key, value := range section {
+			if opts.checkValue(value) != "" {
+				section[key] = strconv.Quote(value)
+			}
+		}
+	}
+	bufout := bufio.NewWriter(w)
+	bufout.WriteString("; effective configuration, secret values are redacted\n\n")
+	if err := sanitized.write(bufout, opts); err != nil {
+		return err
+	}
+	return bufout.Flush()
+}
@@ -0,0 +1,71 @@
+package ini
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSupportDump(t *testing.T) {
+	l := WithDefaults(MustParse("[db]\nhost = db.local\nPassword = hunter2\nempty_token =\n[api]\nurl = https://user:pw@example.com"),
+		map[string]map[string]string{"db": {"port": "5432"}})
+	l.Stack[1].Name = "app.ini"
+
+	var buf strings.Builder
+	err := l.File().SupportDump(&buf, RedactionRules{
+		Redact: func(section, key, value string) bool {
+			return strings.Contains(value, "@")
+		},
+		Origins: l.Origins(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "; effective configuration, secret values are redacted\n\n" +
+		"[api]\n; from app.ini\nurl = <redacted>\n\n" +
+		"[db]\n; from app.ini\nPassword = <redacted>\n; from app.ini\nempty_token =\n" +
+		"; from app.ini\nhost = db.local\n; from defaults\nport = 5432\n"
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, buf.String())
+	}
+
+	buf.Reset()
+	MustParse("user = me\npassword = x").SupportDump(&buf, RedactionRules{KeyPatterns: []string{"user"}, Replacement: "***"})
+	if !strings.Contains(buf.String(), "user = ***\n") || !strings.Contains(buf.String(), "password = x\n") {
+		t.Errorf("custom rules not applied:\n%s", buf.String())
+	}
+}
+
+func TestDefaultRedaction(t *testing.T) {
+	var r RedactionRules
+	for _, key := range []string{"api-key", "db_pass", "pass", "auth", "Authorization", "authToken", "SSH.KEY", "apiKey"} {
+		if !r.redacts("", key, "x") {
+			t.Errorf("expected %q to be redacted", key)
+		}
+	}
+	for _, key := range []string{"keyboard", "passage", "author", "monkey", "host"} {
+		if r.redacts("", key, "x") {
+			t.Errorf("expected %q not to be redacted", key)
+		}
+	}
+	if (RedactionRules{KeyWords: []string{}}).redacts("", "db_pass", "x") {
+		t.Error("expected no words to be redacted with empty KeyWords")
+	}
+}
+
+func TestSupportDumpMultiLineValues(t *testing.T) {
+	f := File{"s": {"list": "a\nb", "text": "a\n\nb", "note": "x\n; y", "a=b": "c", "pad": " x "}}
+	var buf strings.Builder
+	if err := f.SupportDump(&buf, RedactionRules{}); err != nil {
+		t.Fatal(err)
+	}
+	expect := "; effective configuration, secret values are redacted\n\n" +
+		"[s]\n" +
+		"a=b = c\n" +
+		"list = a\n    b\n" +
+		"note = \"x\\n; y\"\n" +
+		"pad = \" x \"\n" +
+		"text = \"a\\n\\nb\"\n"
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, buf.String())
+	}
+}
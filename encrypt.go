@@ -0,0 +1,62 @@
+package ini
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// A Cipher encrypts and decrypts whole files, see Encrypted. Implementations
+// can wrap any scheme, e.g. age or a key management service.
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// Encrypted makes writing encrypt the whole output with c, and reading decrypt
+// the whole input with c before parsing it, so the file never sits on disk in
+// plain text. Combined with Signed, the signature is part of the encrypted
+// content. See AESGCM for a Cipher from the standard library.
+func Encrypted(c Cipher) Option {
+	return func(o *options) {
+		o.cipher = c
+	}
+}
+
+// AESGCM returns a Cipher that uses AES in Galois/Counter Mode with a random
+// nonce per encryption. The key must be 16, 24 or 32 bytes long to select
+// AES-128, AES-192 or AES-256.
+func AESGCM(key []byte) (Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return aesGCM{gcm}, nil
+}
+
+type aesGCM struct {
+	aead cipher.AEAD
+}
+
+// Encrypt returns the nonce followed by the sealed plaintext.
+func (c aesGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (c aesGCM) Decrypt(ciphertext []byte) ([]byte, error) {
+	n := c.aead.NonceSize()
+	if len(ciphertext) < n {
+		return nil, errors.New("ciphertext too short")
+	}
+	return c.aead.Open(nil, ciphertext[:n], ciphertext[n:], nil)
+}
@@ -0,0 +1,47 @@
+package ini
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEncrypted(t *testing.T) {
+	c, err := AESGCM(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := MustParse("[db]\npassword = hunter2")
+	path := filepath.Join(t.TempDir(), "secret.ini")
+	if err := f.Save(path, Encrypted(c), Signed([]byte("key"))); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("hunter2")) || bytes.Contains(data, []byte("[db]")) {
+		t.Fatalf("file is not encrypted:\n%s", data)
+	}
+
+	back, err := Load(path, Encrypted(c), Signed([]byte("key")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, f) {
+		t.Errorf("expected %v, got %v", f, back)
+	}
+
+	other, _ := AESGCM(bytes.Repeat([]byte{8}, 32))
+	if _, err := Load(path, Encrypted(other)); err == nil {
+		t.Error("expected error decrypting with the wrong key")
+	}
+	if _, err := Read(bytes.NewReader([]byte("x")), Encrypted(c)); err == nil {
+		t.Error("expected error for short input")
+	}
+	if _, err := AESGCM([]byte("short")); err == nil {
+		t.Error("expected error for invalid key size")
+	}
+}
@@ -0,0 +1,112 @@
+package ini
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// UnicodeEscapes makes Read and Load decode the escape sequences \uXXXX and
+// \UXXXXXXXX in section names, keys and values, where X is a hexadecimal
+// digit. UTF-16 surrogate pairs written as two \u escapes, as Java properties
+// files do, are combined. Backslashes that do not start a valid escape are kept
+// as they are.
+func UnicodeEscapes() Option {
+	return func(o *options) {
+		o.unicodeEscapes = true
+	}
+}
+
+// EscapeNonASCII makes File.Write and File.Save write all non-ASCII characters
+// as \uXXXX escapes, using surrogate pairs for characters outside the Basic
+// Multilingual Plane. The output is then pure ASCII and can be read back with
+// UnicodeEscapes.
+func EscapeNonASCII() Option {
+	return func(o *options) {
+		o.escapeNonASCII = true
+	}
+}
+
+// decodeUnicodeEscapes replaces all \u and \U escapes in s.
+func decodeUnicodeEscapes(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for len(s) > 0 {
+		r, n := unicodeEscape(s)
+		if n == 0 {
+			b.WriteByte(s[0])
+			s = s[1:]
+			continue
+		}
+		s = s[n:]
+		if utf16.IsSurrogate(r) {
+			if low, m := unicodeEscape(s); m > 0 {
+				if pair := utf16.DecodeRune(r, low); pair != utf8.RuneError {
+					r = pair
+					s = s[m:]
+				}
+			}
+		}
+		if !utf8.ValidRune(r) {
+			r = utf8.RuneError
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// unicodeEscape decodes the escape at the start of s and returns the rune and
+// the length of the escape, or 0 if s does not start with a valid escape.
+func unicodeEscape(s string) (r rune, n int) {
+	if len(s) < 2 || s[0] != '\\' {
+		return 0, 0
+	}
+	switch s[1] {
+	case 'u':
+		n = 6
+	case 'U':
+		n = 10
+	default:
+		return 0, 0
+	}
+	if len(s) < n {
+		return 0, 0
+	}
+	for _, c := range s[2:n] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return 0, 0
+		}
+	}
+	code, err := strconv.ParseUint(s[2:n], 16, 32)
+	if err != nil {
+		return 0, 0
+	}
+	return rune(code), n
+}
+
+// encodeNonASCII replaces all non-ASCII characters in s with \u escapes. A
+// backslash followed by u or U is escaped as well, so that it is not mistaken
+// for the start of an escape when reading.
+func encodeNonASCII(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r == '\\' && i+1 < len(s) && (s[i+1] == 'u' || s[i+1] == 'U') {
+			b.WriteString(`\u005C`)
+			continue
+		}
+		if r < utf8.RuneSelf {
+			b.WriteRune(r)
+			continue
+		}
+		if r1, r2 := utf16.EncodeRune(r); r1 != utf8.RuneError {
+			fmt.Fprintf(&b, `\u%04X\u%04X`, r1, r2)
+		} else {
+			fmt.Fprintf(&b, `\u%04X`, r)
+		}
+	}
+	return b.String()
+}
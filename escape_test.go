@@ -0,0 +1,52 @@
+package ini
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestUnicodeEscapes(t *testing.T) {
+	f := MustParse(`[gr\u00FC\u00DF]
+name = Caf\u00e9
+emoji = \uD83D\uDE00 and \U0001F600
+path = C:\Users\me
+broken = \u12 \uD83D alone \uzzzz`, UnicodeEscapes())
+	expect := File{"grüß": {
+		"name":   "Café",
+		"emoji":  "😀 and 😀",
+		"path":   `C:\Users\me`,
+		"broken": `\u12 ` + "\uFFFD" + ` alone \uzzzz`,
+	}}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %q, got %q", expect, f)
+	}
+
+	if plain := MustParse(`a = \u00e9`); plain[""]["a"] != `\u00e9` {
+		t.Error("expected escapes to be kept without the option")
+	}
+}
+
+func TestEscapeNonASCII(t *testing.T) {
+	f := File{"grüß": {"name": "Café 😀"}}
+	var buf bytes.Buffer
+	if err := f.Write(&buf, EscapeNonASCII()); err != nil {
+		t.Fatal(err)
+	}
+	expect := "[gr\\u00FC\\u00DF]\nname = Caf\\u00E9 \\uD83D\\uDE00\n"
+	if buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+	if back := MustParse(buf.String(), UnicodeEscapes()); !reflect.DeepEqual(back, f) {
+		t.Errorf("round trip: expected %q, got %q", f, back)
+	}
+
+	f = File{"": {"a": `\u0041 \x C:\Users`}}
+	buf.Reset()
+	if err := f.Write(&buf, EscapeNonASCII()); err != nil {
+		t.Fatal(err)
+	}
+	if back := MustParse(buf.String(), UnicodeEscapes()); !reflect.DeepEqual(back, f) {
+		t.Errorf("literal backslashes must round-trip, expected %q, got %q", f, back)
+	}
+}
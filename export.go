@@ -0,0 +1,93 @@
+package ini
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// String returns the lower case name of the kind, e.g. "comment".
+func (k NodeKind) String() string {
+	switch k {
+	case BlankNode:
+		return "blank"
+	case CommentNode:
+		return "comment"
+	case SectionNode:
+		return "section"
+	case KeyNode:
+		return "key"
+	}
+	return "unknown"
+}
+
+// jsonDocument is the JSON representation of a Document, see MarshalJSON.
+type jsonDocument struct {
+	LineBreak    string     `json:"lineBreak"`
+	FinalNewline bool       `json:"finalNewline"`
+	Nodes        []jsonNode `json:"nodes"`
+}
+
+type jsonNode struct {
+	Kind        string `json:"kind"`
+	Line        int    `json:"line"`
+	EndLine     int    `json:"endLine"`
+	Column      int    `json:"column"`
+	Raw         string `json:"raw"`
+	Section     string `json:"section"`
+	Key         string `json:"key,omitempty"`
+	Value       string `json:"value,omitempty"`
+	ValueColumn *int   `json:"valueColumn,omitempty"`
+}
+
+// MarshalJSON encodes the Document for editor tooling, e.g. a language
+// server. Every node is an object with these fields:
+//
+//	kind         "blank", "comment", "section" or "key"
+//	line         the first line of the node, counting from 1
+//	endLine      the last line, which differs for continued values
+//	column       the byte offset of the first non-blank character in line,
+//	             counting from 0
+//	raw          the original text, continued lines separated by \n
+//	section      the section name, or the section the node is in
+//	key          the key of a key node
+//	value        the value of a key node, or the text of a comment
+//	valueColumn  the byte offset of the value in line, for key nodes with a
+//	             delimiter
+//
+// Nodes added to the Document after reading have line 0. The top-level object
+// also contains lineBreak and finalNewline.
+func (d *Document) MarshalJSON() ([]byte, error) {
+	doc := jsonDocument{
+		LineBreak:    d.LineBreak,
+		FinalNewline: d.FinalNewline,
+		Nodes:        make([]jsonNode, 0, len(d.Nodes)),
+	}
+	delimiters := d.options().delimiterChars()
+	for _, n := range d.Nodes {
+		first := n.Raw
+		if i := strings.IndexByte(first, '\n'); i != -1 {
+			first = first[:i]
+		}
+		node := jsonNode{
+			Kind:    n.Kind.String(),
+			Line:    n.Line,
+			Column:  len(first) - len(strings.TrimLeft(first, " \t")),
+			Raw:     n.Raw,
+			Section: n.Section,
+			Key:     n.Key,
+			Value:   n.Value,
+		}
+		if n.Line > 0 {
+			node.EndLine = n.Line + strings.Count(n.Raw, "\n")
+		}
+		if n.Kind == KeyNode {
+			if i := strings.IndexAny(first, delimiters); i != -1 {
+				rest := first[i+1:]
+				col := i + 1 + len(rest) - len(strings.TrimLeft(rest, " \t"))
+				node.ValueColumn = &col
+			}
+		}
+		doc.Nodes = append(doc.Nodes, node)
+	}
+	return json.Marshal(doc)
+}
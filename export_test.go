@@ -0,0 +1,28 @@
+package ini
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDocumentJSON(t *testing.T) {
+	src := "; settings\n\n[server]\n  host = example.com\nlist = a\n  b"
+	d, err := ParseDocument(strings.NewReader(src), Continuations(IndentContinuation))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"lineBreak":"\n","finalNewline":false,"nodes":[` +
+		`{"kind":"comment","line":1,"endLine":1,"column":0,"raw":"; settings","section":"","value":"settings"},` +
+		`{"kind":"blank","line":2,"endLine":2,"column":0,"raw":"","section":""},` +
+		`{"kind":"section","line":3,"endLine":3,"column":0,"raw":"[server]","section":"server"},` +
+		`{"kind":"key","line":4,"endLine":4,"column":2,"raw":"  host = example.com","section":"server","key":"host","value":"example.com","valueColumn":9},` +
+		`{"kind":"key","line":5,"endLine":6,"column":0,"raw":"list = a\n  b","section":"server","key":"list","value":"a\nb","valueColumn":7}]}`
+	if string(data) != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, data)
+	}
+}
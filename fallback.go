@@ -0,0 +1,53 @@
+package ini
+
+// Get looks up the value for a key in the section, like a map lookup.
+func (s Section) Get(key string) (value string, ok bool) {
+	value, ok = s[key]
+	return value, ok
+}
+
+// FallbackChain is a list of Sections in order of decreasing precedence. Get
+// returns the value from the first Section that contains the key. The Sections
+// are not copied, changes to them are visible through the chain.
+//
+// Since a Section is a map, a view that looks up values in other Sections can
+// not itself be a Section. Use Flatten to get one.
+type FallbackChain []Section
+
+// WithFallback returns a FallbackChain that looks up keys in s first and in
+// other if s does not contain them. Chains can be extended, e.g.
+//
+//	instance.WithFallback(role).WithFallback(global)
+func (s Section) WithFallback(other Section) FallbackChain {
+	return FallbackChain{s, other}
+}
+
+// WithFallback returns a new FallbackChain with other appended as the Section
+// with the lowest precedence. The receiver is not modified.
+func (c FallbackChain) WithFallback(other Section) FallbackChain {
+	chain := make(FallbackChain, 0, len(c)+1)
+	return append(append(chain, c...), other)
+}
+
+// Get returns the value for key from the first Section in the chain that
+// contains it.
+func (c FallbackChain) Get(key string) (value string, ok bool) {
+	for _, s := range c {
+		if value, ok = s.Get(key); ok {
+			return
+		}
+	}
+	return "", false
+}
+
+// Flatten returns a new Section with the values that Get would return for all
+// keys in the chain.
+func (c FallbackChain) Flatten() Section {
+	flat := make(Section)
+	for i := len(c) - 1; i >= 0; i-- {
+		for key, value := range c[i] {
+			flat[key] = value
+		}
+	}
+	return flat
+}
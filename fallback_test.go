@@ -0,0 +1,42 @@
+package ini
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithFallback(t *testing.T) {
+	f := MustParse("[global]\nport = 80\nlog = info\nuser = www\n" +
+		"[role]\nport = 8080\nlog = debug\n" +
+		"[instance]\nport = 9000")
+	chain := f.Section("instance").WithFallback(f.Section("role")).WithFallback(f.Section("global"))
+
+	check := func(key, expect string, expectOK bool) {
+		t.Helper()
+		value, ok := chain.Get(key)
+		if value != expect || ok != expectOK {
+			t.Errorf("%s: expected %q, %v but got %q, %v", key, expect, expectOK, value, ok)
+		}
+	}
+	check("port", "9000", true)
+	check("log", "debug", true)
+	check("user", "www", true)
+	check("missing", "", false)
+
+	// The chain is a view, changes to the sections are visible.
+	f.Set("global", "user", "nobody")
+	check("user", "nobody", true)
+
+	flat := chain.Flatten()
+	expect := Section{"port": "9000", "log": "debug", "user": "nobody"}
+	if !reflect.DeepEqual(flat, expect) {
+		t.Errorf("expected %q, got %q", expect, flat)
+	}
+
+	// Extending a chain does not change the original.
+	short := f.Section("instance").WithFallback(f.Section("role"))
+	_ = short.WithFallback(f.Section("global"))
+	if len(short) != 2 {
+		t.Errorf("chain was modified: %d sections", len(short))
+	}
+}
@@ -0,0 +1,45 @@
+package ini
+
+import (
+	"sort"
+	"strings"
+)
+
+// Flatten returns all values of the File in a single map with keys of the
+// form section + sep + key, e.g. "server.port" for sep ".", as used by
+// environment variables and flag libraries. Keys of the default section have
+// no prefix. See SetFlat for the inverse.
+func (f File) Flatten(sep string) map[string]string {
+	flat := make(map[string]string)
+	for name, section := range f {
+		for key, value := range section {
+			if name != "" {
+				key = name + sep + key
+			}
+			flat[key] = value
+		}
+	}
+	return flat
+}
+
+// SetFlat stores the values of a map made by Flatten, or by another
+// configuration system, in the File. Keys are split at the last sep into
+// section and key, so "server.http.port" sets the key port in the section
+// [server.http] for sep ".". Keys without sep go to the default section. The
+// options are passed on to Set.
+func (f File) SetFlat(flat map[string]string, sep string, opts ...Option) {
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	// Set is called in a fixed order so that audit records and KeepOrder are
+	// the same every time.
+	sort.Strings(keys)
+	for _, flatKey := range keys {
+		section, key := "", flatKey
+		if i := strings.LastIndex(flatKey, sep); i != -1 && sep != "" {
+			section, key = flatKey[:i], flatKey[i+len(sep):]
+		}
+		f.Set(section, key, flat[flatKey], opts...)
+	}
+}
@@ -0,0 +1,35 @@
+package ini
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlatten(t *testing.T) {
+	f := File{
+		"":            {"debug": "true"},
+		"server":      {"port": "80"},
+		"server.http": {"timeout": "5s"},
+	}
+	flat := f.Flatten(".")
+	expect := map[string]string{
+		"debug":               "true",
+		"server.port":         "80",
+		"server.http.timeout": "5s",
+	}
+	if !reflect.DeepEqual(flat, expect) {
+		t.Errorf("expected %v, got %v", expect, flat)
+	}
+
+	back := File{}
+	back.SetFlat(flat, ".")
+	if !reflect.DeepEqual(back, f) {
+		t.Errorf("expected %v, got %v", f, back)
+	}
+
+	env := File{}
+	env.SetFlat(map[string]string{"DB__HOST": "x", "PORT": "1"}, "__")
+	if !reflect.DeepEqual(env, File{"DB": {"HOST": "x"}, "": {"PORT": "1"}}) {
+		t.Errorf("unexpected file %v", env)
+	}
+}
@@ -0,0 +1,116 @@
+package ini
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// GenerateStruct returns the source of a Go file in package pkg that declares
+// a struct type typeName matching the layout of f. Keys of the default section
+// become fields of the struct itself, every other section becomes a nested
+// struct field. All fields carry an ini tag with their original name.
+//
+// Field types are inferred from the example values: int, float64, bool,
+// time.Duration or string.
+func GenerateStruct(f File, pkg, typeName string) ([]byte, error) {
+	var body bytes.Buffer
+	usesTime := false
+
+	writeFields := func(section Section, indent string, names map[string]bool) {
+		for _, key := range sortedSectionKeys(section) {
+			typ := inferType(section[key])
+			if typ == "time.Duration" {
+				usesTime = true
+			}
+			name := uniqueName(goName(key), names)
+			fmt.Fprintf(&body, "%s%s %s `ini:%q`\n", indent, name, typ, key)
+		}
+	}
+
+	fmt.Fprintf(&body, "type %s struct {\n", typeName)
+	names := make(map[string]bool)
+	writeFields(f[""], "\t", names)
+	for _, section := range sortedKeys(f) {
+		if section == "" {
+			continue
+		}
+		name := uniqueName(goName(section), names)
+		fmt.Fprintf(&body, "\t%s struct {\n", name)
+		writeFields(f[section], "\t\t", make(map[string]bool))
+		fmt.Fprintf(&body, "\t} `ini:%q`\n", section)
+	}
+	body.WriteString("}\n")
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by ini gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+	if usesTime {
+		src.WriteString("import \"time\"\n\n")
+	}
+	body.WriteTo(&src)
+	return format.Source(src.Bytes())
+}
+
+// inferType returns the Go type that best fits an example value.
+func inferType(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return "int"
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return "float64"
+	}
+	switch strings.ToLower(value) {
+	case "true", "false", "yes", "no", "on", "off":
+		return "bool"
+	}
+	if _, err := time.ParseDuration(value); err == nil {
+		return "time.Duration"
+	}
+	return "string"
+}
+
+var initialisms = map[string]bool{
+	"API": true, "DNS": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "SQL": true, "TLS": true, "URL": true,
+}
+
+// goName turns a section or key name into an exported Go identifier, e.g.
+// max_conn-count becomes MaxConnCount and server url becomes ServerURL.
+func goName(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var name strings.Builder
+	for _, word := range words {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			name.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		name.WriteRune(unicode.ToUpper(runes[0]))
+		name.WriteString(string(runes[1:]))
+	}
+	if name.Len() == 0 {
+		return "Field"
+	}
+	if first := []rune(name.String())[0]; !unicode.IsLetter(first) {
+		return "X" + name.String()
+	}
+	return name.String()
+}
+
+// uniqueName returns name, with a number appended if it is already used.
+// The returned name is added to used.
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
@@ -0,0 +1,53 @@
+package ini
+
+import "testing"
+
+func TestGenerateStruct(t *testing.T) {
+	f := MustParse(`
+debug = yes
+name = demo
+
+[server]
+port = 8080
+timeout = 1m30s
+ratio = 0.75
+base-url = http://localhost
+[1st section]
+`)
+	src, err := GenerateStruct(f, "config", "Config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "// Code generated by ini gen. DO NOT EDIT.\n\n" +
+		"package config\n\n" +
+		"import \"time\"\n\n" +
+		"type Config struct {\n" +
+		"\tDebug       bool   `ini:\"debug\"`\n" +
+		"\tName        string `ini:\"name\"`\n" +
+		"\tX1stSection struct {\n" +
+		"\t} `ini:\"1st section\"`\n" +
+		"\tServer struct {\n" +
+		"\t\tBaseURL string        `ini:\"base-url\"`\n" +
+		"\t\tPort    int           `ini:\"port\"`\n" +
+		"\t\tRatio   float64       `ini:\"ratio\"`\n" +
+		"\t\tTimeout time.Duration `ini:\"timeout\"`\n" +
+		"\t} `ini:\"server\"`\n" +
+		"}\n"
+	if string(src) != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, src)
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for in, expect := range map[string]string{
+		"max_conn-count": "MaxConnCount",
+		"server url":     "ServerURL",
+		"id":             "ID",
+		"9lives":         "X9lives",
+		"--":             "Field",
+	} {
+		if got := goName(in); got != expect {
+			t.Errorf("goName(%q): expected %q, got %q", in, expect, got)
+		}
+	}
+}
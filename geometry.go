@@ -0,0 +1,64 @@
+package ini
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Resolution is a width and height, as used for window and screen sizes.
+type Resolution struct {
+	Width  int
+	Height int
+}
+
+// Point is a two-dimensional position, as used for window positions.
+type Point struct {
+	X int
+	Y int
+}
+
+// GetResolution parses the value for a key in a section as WIDTHxHEIGHT, e.g.
+// 1920x1080. Neither dimension may be negative.
+func (f File) GetResolution(section, key string) (Resolution, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return Resolution{}, err
+	}
+	w, h, err := parsePair(strings.ToLower(value), "x")
+	if err == nil && (w < 0 || h < 0) {
+		err = errors.New("resolution must not be negative")
+	}
+	if err != nil {
+		return Resolution{}, ErrValue{section, key, value, err}
+	}
+	return Resolution{w, h}, nil
+}
+
+// GetPoint parses the value for a key in a section as a pair of integers
+// separated by a comma, e.g. 100,-20.
+func (f File) GetPoint(section, key string) (Point, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return Point{}, err
+	}
+	x, y, err := parsePair(value, ",")
+	if err != nil {
+		return Point{}, ErrValue{section, key, value, err}
+	}
+	return Point{x, y}, nil
+}
+
+func parsePair(s, sep string) (a, b int, err error) {
+	parts := strings.Split(s, sep)
+	if len(parts) != 2 {
+		return 0, 0, errors.New("expected two integers separated by " + strconv.Quote(sep))
+	}
+	if a, err = strconv.Atoi(strings.TrimSpace(parts[0])); err != nil {
+		return 0, 0, err
+	}
+	if b, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil {
+		return 0, 0, err
+	}
+	return a, b, nil
+}
@@ -0,0 +1,28 @@
+package ini
+
+import "testing"
+
+func TestGetResolutionAndPoint(t *testing.T) {
+	file := mustRead(t, `[window]
+size = 1920x1080
+spaced = 800 X 600
+negative = -1x10
+position = 100, -20
+broken = 100`)
+
+	if r, err := file.GetResolution("window", "size"); err != nil || r != (Resolution{1920, 1080}) {
+		t.Errorf("size: got %v, %v", r, err)
+	}
+	if r, err := file.GetResolution("window", "spaced"); err != nil || r != (Resolution{800, 600}) {
+		t.Errorf("spaced: got %v, %v", r, err)
+	}
+	if _, err := file.GetResolution("window", "negative"); err == nil {
+		t.Error("expected error for negative resolution")
+	}
+	if p, err := file.GetPoint("window", "position"); err != nil || p != (Point{100, -20}) {
+		t.Errorf("position: got %v, %v", p, err)
+	}
+	if _, err := file.GetPoint("window", "broken"); err == nil {
+		t.Error("expected error for single coordinate")
+	}
+}
@@ -0,0 +1,379 @@
+package ini
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned by the typed getters when a key does not exist.
+type ErrNotFound struct {
+	Section string
+	Key     string
+}
+
+func (e ErrNotFound) Error() string {
+	return fmt.Sprintf("key %q not found in section %q", e.Key, e.Section)
+}
+
+// ErrValue is returned by the typed getters when a value exists but cannot be
+// converted to the requested type.
+type ErrValue struct {
+	Section string
+	Key     string
+	Value   string
+	Err     error // the underlying conversion error
+}
+
+func (e ErrValue) Error() string {
+	return fmt.Sprintf("invalid value %q for key %q in section %q: %v",
+		e.Value, e.Key, e.Section, e.Err)
+}
+
+func (e ErrValue) Unwrap() error {
+	return e.Err
+}
+
+// lookup returns the value for a key or an ErrNotFound if it does not exist.
+func (f File) lookup(section, key string) (string, error) {
+	value, ok := f.Get(section, key)
+	if !ok {
+		return "", ErrNotFound{section, key}
+	}
+	return value, nil
+}
+
+// GetInt parses the value for a key in a section as an integer. It is decimal
+// unless it has one of the prefixes 0x for hexadecimal, 0o for octal or 0b for
+// binary, e.g. 0xFF. See LocaleNumbers for a more lenient number format.
+func (f File) GetInt(section, key string, opts ...Option) (int, error) {
+	n, err := f.getInt(section, key, strconv.IntSize, opts)
+	return int(n), err
+}
+
+// GetInt8 is like GetInt but returns an error for values outside the range of
+// an int8.
+func (f File) GetInt8(section, key string, opts ...Option) (int8, error) {
+	n, err := f.getInt(section, key, 8, opts)
+	return int8(n), err
+}
+
+// GetInt16 is like GetInt but returns an error for values outside the range of
+// an int16.
+func (f File) GetInt16(section, key string, opts ...Option) (int16, error) {
+	n, err := f.getInt(section, key, 16, opts)
+	return int16(n), err
+}
+
+// GetInt32 is like GetInt but returns an error for values outside the range of
+// an int32.
+func (f File) GetInt32(section, key string, opts ...Option) (int32, error) {
+	n, err := f.getInt(section, key, 32, opts)
+	return int32(n), err
+}
+
+// GetInt64 is like GetInt but returns an int64.
+func (f File) GetInt64(section, key string, opts ...Option) (int64, error) {
+	return f.getInt(section, key, 64, opts)
+}
+
+func (f File) getInt(section, key string, bits int, opts []Option) (int64, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return 0, err
+	}
+	s := value
+	if makeOptions(opts).localeNumbers {
+		s = removeDigitGrouping(s)
+	}
+	n, err := parseInt(s, bits)
+	if err != nil {
+		return 0, ErrValue{section, key, value, err}
+	}
+	return n, nil
+}
+
+// parseInt parses a decimal integer or one with a base prefix, see GetInt.
+func parseInt(s string, bits int) (int64, error) {
+	sign, digits := "", s
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		sign, digits = s[:1], s[1:]
+	}
+	base, digits := basePrefix(digits)
+	if base != 10 && (digits[0] == '-' || digits[0] == '+') {
+		// The sign comes before the prefix, not after it.
+		return 0, &strconv.NumError{Func: "ParseInt", Num: s, Err: strconv.ErrSyntax}
+	}
+	return strconv.ParseInt(sign+digits, base, bits)
+}
+
+// parseUint is like parseInt for unsigned integers.
+func parseUint(s string, bits int) (uint64, error) {
+	base, digits := basePrefix(s)
+	return strconv.ParseUint(digits, base, bits)
+}
+
+// basePrefix returns the base of an integer with a 0x, 0o or 0b prefix and
+// the digits after the prefix, or 10 and s if it has none.
+func basePrefix(s string) (base int, digits string) {
+	if len(s) > 2 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X':
+			return 16, s[2:]
+		case 'o', 'O':
+			return 8, s[2:]
+		case 'b', 'B':
+			return 2, s[2:]
+		}
+	}
+	return 10, s
+}
+
+// GetUint8 is like GetInt but returns an error for negative values and values
+// outside the range of a uint8, e.g. a byte of a network mask.
+func (f File) GetUint8(section, key string, opts ...Option) (uint8, error) {
+	n, err := f.getUint(section, key, 8, opts)
+	return uint8(n), err
+}
+
+// GetUint16 is like GetInt but returns an error for negative values and values
+// outside the range of a uint16, e.g. a port number.
+func (f File) GetUint16(section, key string, opts ...Option) (uint16, error) {
+	n, err := f.getUint(section, key, 16, opts)
+	return uint16(n), err
+}
+
+// GetUint32 is like GetInt but returns an error for negative values and values
+// outside the range of a uint32.
+func (f File) GetUint32(section, key string, opts ...Option) (uint32, error) {
+	n, err := f.getUint(section, key, 32, opts)
+	return uint32(n), err
+}
+
+// GetUint64 is like GetInt but returns an error for negative values and values
+// outside the range of a uint64.
+func (f File) GetUint64(section, key string, opts ...Option) (uint64, error) {
+	return f.getUint(section, key, 64, opts)
+}
+
+func (f File) getUint(section, key string, bits int, opts []Option) (uint64, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return 0, err
+	}
+	s := value
+	if makeOptions(opts).localeNumbers {
+		s = removeDigitGrouping(s)
+	}
+	n, err := parseUint(s, bits)
+	if err != nil {
+		return 0, ErrValue{section, key, value, err}
+	}
+	return n, nil
+}
+
+// GetFloat parses the value for a key in a section as a floating-point number.
+// See LocaleNumbers for a more lenient number format.
+func (f File) GetFloat(section, key string, opts ...Option) (float64, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return 0, err
+	}
+	s := value
+	if makeOptions(opts).localeNumbers {
+		s = removeDigitGrouping(s)
+		if strings.Count(s, ",") == 1 && !strings.Contains(s, ".") {
+			s = strings.Replace(s, ",", ".", 1)
+		}
+	}
+	x, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, ErrValue{section, key, value, err}
+	}
+	return x, nil
+}
+
+// GetBool parses the value for a key in a section as a boolean. The values
+// true, yes, on and 1 are true, false, no, off and 0 are false, case is
+// ignored. See EmptyBoolAs for how empty values are handled.
+func (f File) GetBool(section, key string, opts ...Option) (bool, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return false, err
+	}
+	if b, ok := parseBool(value); ok {
+		return b, nil
+	}
+	if value == "" {
+		switch makeOptions(opts).emptyBool {
+		case EmptyTrue:
+			return true, nil
+		case EmptyFalse:
+			return false, nil
+		case EmptyUnset:
+			return false, ErrNotFound{section, key}
+		}
+	}
+	return false, ErrValue{section, key, value, errors.New("not a boolean")}
+}
+
+// GetAny returns the value for a key in a section converted to the type that
+// it looks like, for tools that dump or convert configurations without knowing
+// what the keys mean. It tries these types in order:
+//
+//	int64          integers like 42, -7 or 0xff, see GetInt
+//	float64        decimal numbers like 1.5 or 2e10, not Inf or NaN
+//	bool           the words accepted by GetBool, but not 0 and 1
+//	time.Duration  durations like 1h30m
+//	string         everything else
+//
+// The only error is ErrNotFound.
+func (f File) GetAny(section, key string) (interface{}, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return nil, err
+	}
+	return sniffValue(value), nil
+}
+
+func sniffValue(s string) interface{} {
+	if n, err := parseInt(s, 64); err == nil {
+		return n
+	}
+	if looksNumeric(s) {
+		if x, err := strconv.ParseFloat(s, 64); err == nil {
+			return x
+		}
+	}
+	if b, ok := parseBool(s); ok {
+		return b
+	}
+	if looksNumeric(s) {
+		if d, err := time.ParseDuration(s); err == nil {
+			return d
+		}
+	}
+	return s
+}
+
+// looksNumeric reports whether s starts like a number, to keep words like NaN
+// from being parsed as numbers.
+func looksNumeric(s string) bool {
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	return s != "" && ('0' <= s[0] && s[0] <= '9' || s[0] == '.')
+}
+
+// parseBool parses the boolean values accepted by GetBool.
+func parseBool(s string) (value, ok bool) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on", "1":
+		return true, true
+	case "false", "no", "off", "0":
+		return false, true
+	}
+	return false, false
+}
+
+// removeDigitGrouping removes the characters commonly used to group digits.
+func removeDigitGrouping(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '\u00A0', '\u2009', '\u202F':
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// regexpKey identifies a compiled expression in the cache of a File.
+type regexpKey struct {
+	section, key string
+	posix        bool
+}
+
+// compiledRegexp is a cached expression and the value it was compiled from.
+type compiledRegexp struct {
+	expr string
+	re   *regexp.Regexp
+}
+
+// GetStrings returns the lines of a multi-line value as a list, see
+// SetStrings. Lines are trimmed and blank lines are left out.
+func (f File) GetStrings(section, key string) ([]string, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// GetRegexp compiles the value for a key in a section with regexp.Compile.
+// The File caches the expression of every key so repeated lookups do not
+// recompile it. The cache holds one expression per key and is dropped with the
+// File.
+func (f File) GetRegexp(section, key string) (*regexp.Regexp, error) {
+	return f.getRegexp(section, key, false)
+}
+
+// GetRegexpPOSIX is like GetRegexp but compiles the value with
+// regexp.CompilePOSIX.
+func (f File) GetRegexpPOSIX(section, key string) (*regexp.Regexp, error) {
+	return f.getRegexp(section, key, true)
+}
+
+func (f File) getRegexp(section, key string, posix bool) (*regexp.Regexp, error) {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return nil, err
+	}
+
+	state := stateOf(f, true)
+	cacheKey := regexpKey{section, key, posix}
+	state.Lock()
+	cached, ok := state.regexps[cacheKey]
+	state.Unlock()
+	if ok && cached.expr == value {
+		return cached.re, nil
+	}
+
+	compile := regexp.Compile
+	if posix {
+		compile = regexp.CompilePOSIX
+	}
+	re, err := compile(value)
+	if err != nil {
+		return nil, ErrValue{section, key, value, err}
+	}
+	state.Lock()
+	if state.regexps == nil {
+		state.regexps = make(map[regexpKey]compiledRegexp)
+	}
+	state.regexps[cacheKey] = compiledRegexp{value, re}
+	state.Unlock()
+	return re, nil
+}
+
+// GetJSON unmarshals the value for a key in a section into v, as
+// json.Unmarshal does.
+func (f File) GetJSON(section, key string, v interface{}) error {
+	value, err := f.lookup(section, key)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return ErrValue{section, key, value, err}
+	}
+	return nil
+}
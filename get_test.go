@@ -0,0 +1,203 @@
+package ini
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustRead(t *testing.T, src string) File {
+	t.Helper()
+	file, err := Read(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestGetRegexp(t *testing.T) {
+	file := mustRead(t, "[filter]\nignore = ^tmp/.*$\nbroken = (")
+
+	re, err := file.GetRegexp("filter", "ignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("tmp/file") || re.MatchString("src/tmp/file") {
+		t.Errorf("unexpected matches for %v", re)
+	}
+	again, _ := file.GetRegexp("filter", "ignore")
+	if again != re {
+		t.Error("expected compiled expression to be cached")
+	}
+	if posix, err := file.GetRegexpPOSIX("filter", "ignore"); err != nil || posix == re {
+		t.Errorf("expected separate POSIX expression, got %v, %v", posix, err)
+	}
+
+	if _, err := file.GetRegexp("filter", "broken"); err == nil {
+		t.Error("expected error for invalid expression")
+	} else if _, ok := err.(ErrValue); !ok {
+		t.Errorf("expected ErrValue, got %T", err)
+	}
+	if _, err := file.GetRegexp("filter", "missing"); err != (ErrNotFound{"filter", "missing"}) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if other, _ := mustRead(t, "[filter]\nignore = ^tmp/.*$").GetRegexp("filter", "ignore"); other == re {
+		t.Error("every File must have its own cache")
+	}
+
+	for i := 0; i < 10; i++ {
+		file.Set("filter", "ignore", "^x"+strconv.Itoa(i)+"$")
+		re, err := file.GetRegexp("filter", "ignore")
+		if err != nil || !re.MatchString("x"+strconv.Itoa(i)) {
+			t.Fatalf("expected the changed value to be compiled, got %v, %v", re, err)
+		}
+	}
+	if n := len(stateOf(file, false).regexps); n != 2 {
+		t.Errorf("expected one cached expression per key, got %d", n)
+	}
+}
+
+func TestGetJSON(t *testing.T) {
+	file := mustRead(t, `[plugin]
+settings = {"name": "lint", "levels": [1, 2]}
+broken = {"name":`)
+
+	var settings struct {
+		Name   string
+		Levels []int
+	}
+	if err := file.GetJSON("plugin", "settings", &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings.Name != "lint" || !reflect.DeepEqual(settings.Levels, []int{1, 2}) {
+		t.Errorf("unexpected settings %+v", settings)
+	}
+	if err := file.GetJSON("plugin", "broken", &settings); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestGetNumbers(t *testing.T) {
+	file := mustRead(t, "[n]\nint = 42\ngrouped = 1 000\u2009000\nunderscored = 1_024\npi = 3,14\nbig = 12 345,5\nplain = 2.5")
+
+	if n, err := file.GetInt("n", "int"); err != nil || n != 42 {
+		t.Errorf("int: got %v, %v", n, err)
+	}
+	if _, err := file.GetInt("n", "grouped"); err == nil {
+		t.Error("expected grouped digits to be rejected by default")
+	}
+	if _, err := file.GetFloat("n", "pi"); err == nil {
+		t.Error("expected decimal comma to be rejected by default")
+	}
+
+	if n, err := file.GetInt("n", "grouped", LocaleNumbers()); err != nil || n != 1000000 {
+		t.Errorf("grouped: got %v, %v", n, err)
+	}
+	if n, err := file.GetInt("n", "underscored", LocaleNumbers()); err != nil || n != 1024 {
+		t.Errorf("underscored: got %v, %v", n, err)
+	}
+	for key, expect := range map[string]float64{"pi": 3.14, "big": 12345.5, "plain": 2.5} {
+		if x, err := file.GetFloat("n", key, LocaleNumbers()); err != nil || x != expect {
+			t.Errorf("%s: expected %v, got %v, %v", key, expect, x, err)
+		}
+	}
+}
+
+func TestGetBool(t *testing.T) {
+	file, err := Read(strings.NewReader("[f]\nverbose\nquiet =\nfast = Yes\ncolor = off\nbad = maybe"), FlagKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err := file.GetBool("f", "fast"); err != nil || !b {
+		t.Errorf("fast: got %v, %v", b, err)
+	}
+	if b, err := file.GetBool("f", "color"); err != nil || b {
+		t.Errorf("color: got %v, %v", b, err)
+	}
+	if _, err := file.GetBool("f", "bad"); err == nil {
+		t.Error("expected error for non-boolean")
+	}
+
+	for _, key := range []string{"verbose", "quiet"} {
+		if _, err := file.GetBool("f", key); err == nil {
+			t.Errorf("%s: expected error for empty value by default", key)
+		}
+		if b, err := file.GetBool("f", key, EmptyBoolAs(EmptyTrue)); err != nil || !b {
+			t.Errorf("%s: expected true, got %v, %v", key, b, err)
+		}
+		if b, err := file.GetBool("f", key, EmptyBoolAs(EmptyFalse)); err != nil || b {
+			t.Errorf("%s: expected false, got %v, %v", key, b, err)
+		}
+		if _, err := file.GetBool("f", key, EmptyBoolAs(EmptyUnset)); err != (ErrNotFound{"f", key}) {
+			t.Errorf("%s: expected ErrNotFound, got %v", key, err)
+		}
+	}
+
+	if _, err := Read(strings.NewReader("verbose")); err == nil {
+		t.Error("expected flag-style keys to be a syntax error by default")
+	}
+}
+
+func TestGetSizedInts(t *testing.T) {
+	file := MustParse("[n]\nport = 8080\nbig = 70000\nneg = -129\nmask = 255\nhuge = 18446744073709551615")
+
+	if n, err := file.GetUint16("n", "port"); err != nil || n != 8080 {
+		t.Errorf("port: got %v, %v", n, err)
+	}
+	if _, err := file.GetUint16("n", "big"); err == nil {
+		t.Error("expected 70000 to overflow a uint16")
+	} else if !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("expected range error, got %v", err)
+	}
+	if _, err := file.GetInt8("n", "neg"); err == nil {
+		t.Error("expected -129 to overflow an int8")
+	}
+	if n, err := file.GetInt16("n", "neg"); err != nil || n != -129 {
+		t.Errorf("neg: got %v, %v", n, err)
+	}
+	if _, err := file.GetUint32("n", "neg"); err == nil {
+		t.Error("expected negative value to be rejected as unsigned")
+	}
+	if n, err := file.GetUint8("n", "mask"); err != nil || n != 255 {
+		t.Errorf("mask: got %v, %v", n, err)
+	}
+	if n, err := file.GetUint64("n", "huge"); err != nil || n != 1<<64-1 {
+		t.Errorf("huge: got %v, %v", n, err)
+	}
+	if _, err := file.GetInt64("n", "huge"); err == nil {
+		t.Error("expected huge to overflow an int64")
+	}
+	if n, err := file.GetInt32("n", "big"); err != nil || n != 70000 {
+		t.Errorf("big: got %v, %v", n, err)
+	}
+}
+
+func TestGetAny(t *testing.T) {
+	file := MustParse("int = -42\nfloat = 1.5e3\nbool = Yes\none = 1\ndur = 1h30m\nnan = NaN\nstr = hello\nempty =\nbig = 99999999999999999999")
+	for key, expect := range map[string]interface{}{
+		"int":   int64(-42),
+		"float": 1500.0,
+		"bool":  true,
+		"one":   int64(1),
+		"dur":   90 * time.Minute,
+		"nan":   "NaN",
+		"str":   "hello",
+		"empty": "",
+		"big":   1e20,
+	} {
+		v, err := file.GetAny("", key)
+		if err != nil {
+			t.Errorf("%s: %v", key, err)
+		} else if v != expect {
+			t.Errorf("%s: expected %T %v, got %T %v", key, expect, expect, v, v)
+		}
+	}
+	if _, err := file.GetAny("", "missing"); err != (ErrNotFound{"", "missing"}) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
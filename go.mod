@@ -1,3 +1,3 @@
 module github.com/gonutz/ini
 
-go 1.11
+go 1.24
@@ -0,0 +1,75 @@
+package ini
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// A FileSnapshotter provides the current contents of a configuration, e.g. a
+// SafeFile or Layers.
+type FileSnapshotter interface {
+	File() File
+}
+
+// Handler returns an http.Handler that shows the current configuration of
+// config with values hidden according to redact, like expvar does for
+// variables. Mount it on an internal debug address, e.g.
+//
+//	http.Handle("/debug/config", ini.Handler(layers, ini.RedactionRules{}))
+//
+// It serves HTML, or JSON if the request has the query parameter format=json
+// or accepts application/json. If redact has no Origins and config has an
+// Origins method, like Layers, those are shown.
+func Handler(config FileSnapshotter, redact RedactionRules) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f := redact.apply(config.File())
+		origins := redact.Origins
+		if o, ok := config.(interface{ Origins() Origins }); ok && origins == nil {
+			origins = o.Origins()
+		}
+
+		if r.URL.Query().Get("format") == "json" ||
+			strings.Contains(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+			doc := map[string]interface{}{"values": f}
+			if origins != nil {
+				doc["origins"] = origins
+			}
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			enc.Encode(doc)
+			return
+		}
+
+		type row struct{ Key, Value, Origin string }
+		type section struct {
+			Name string
+			Rows []row
+		}
+		var sections []section
+		for _, name := range sortedKeys(f) {
+			s := section{Name: name}
+			for _, key := range sortedSectionKeys(f[name]) {
+				s.Rows = append(s.Rows, row{key, f[name][key], origins.Get(name, key)})
+			}
+			sections = append(sections, s)
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		handlerTemplate.Execute(w, sections)
+	})
+}
+
+var handlerTemplate = template.Must(template.New("config").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Configuration</title></head>
+<body>
+{{range .}}<h2>[{{.Name}}]</h2>
+<table>
+<tr><th>Key</th><th>Value</th><th>Origin</th></tr>
+{{range .Rows}}<tr><td>{{.Key}}</td><td><pre>{{.Value}}</pre></td><td>{{.Origin}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,48 @@
+package ini
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	l := WithDefaults(MustParse("[db]\nhost = <db>\npassword = hunter2"),
+		map[string]map[string]string{"db": {"port": "5432"}})
+	l.Stack[1].Name = "app.ini"
+	h := Handler(l, RedactionRules{})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/config?format=json", nil))
+	var doc struct {
+		Values  File
+		Origins Origins
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	expect := File{"db": {"host": "<db>", "password": "<redacted>", "port": "5432"}}
+	if !reflect.DeepEqual(doc.Values, expect) {
+		t.Errorf("expected %v, got %v", expect, doc.Values)
+	}
+	if doc.Origins.Get("db", "port") != "defaults" || doc.Origins.Get("db", "host") != "app.ini" {
+		t.Errorf("unexpected origins %v", doc.Origins)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/config", nil))
+	body := rec.Body.String()
+	if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("expected HTML, got %q", rec.Header().Get("Content-Type"))
+	}
+	for _, s := range []string{"[db]", "&lt;db&gt;", "&lt;redacted&gt;", "app.ini", "defaults"} {
+		if !strings.Contains(body, s) {
+			t.Errorf("expected %q in the HTML:\n%s", s, body)
+		}
+	}
+	if strings.Contains(body, "hunter2") || strings.Contains(body, "<db>") {
+		t.Errorf("HTML leaks or does not escape values:\n%s", body)
+	}
+}
@@ -0,0 +1,49 @@
+package ini
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+)
+
+// Hash returns a SHA-256 hash over the contents of the File. Sections and keys
+// are hashed in sorted order so equal Files always have equal hashes,
+// regardless of the order that they were written in. Empty sections are part
+// of the hash.
+func (f File) Hash() [32]byte {
+	h := sha256.New()
+	writeLen := func(n int) {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(n))
+		h.Write(b[:])
+	}
+	write := func(s string) {
+		// Length-prefix every string so that different splits of the same
+		// bytes into sections, keys and values cannot collide.
+		writeLen(len(s))
+		h.Write([]byte(s))
+	}
+	for _, name := range sortedKeys(f) {
+		write(name)
+		section := f[name]
+		keys := sortedSectionKeys(section)
+		writeLen(len(keys))
+		for _, key := range keys {
+			write(key)
+			write(section[key])
+		}
+	}
+	var sum [32]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// sortedKeys returns the section names of f in sorted order.
+func sortedKeys(f File) []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,22 @@
+package ini
+
+import "testing"
+
+func TestHash(t *testing.T) {
+	a := mustRead(t, "[x]\na = 1\nb = 2\n[y]\nc = 3")
+	b := mustRead(t, "[y]\nc=3\n[x]\nb=2\na=1")
+	if a.Hash() != b.Hash() {
+		t.Error("expected equal Files to have equal hashes")
+	}
+
+	different := []File{
+		mustRead(t, "[x]\na = 1\nb = 2\n[y]\nc = 4"),
+		mustRead(t, "[x]\na = 1\nb = 2\nc = 3\n[y]"),
+		mustRead(t, "[x]\na = 1\nb = 2\n[y]\nc = 3\n[z]"),
+	}
+	for i, f := range different {
+		if f.Hash() == a.Hash() {
+			t.Errorf("%d: expected different hash", i)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package ini
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DottedSections makes reading treat section names as paths of segments
+// separated by dots, e.g. [server.http.tls] as looked up by Resolve, and
+// reject headers that do not form a valid path: segments must not be empty or
+// have surrounding white space, and there may be at most maxDepth segments. A
+// maxDepth of 0 or less means no limit. This rejects pathological inputs
+// before a program walks the hierarchy.
+func DottedSections(maxDepth int) Option {
+	return func(o *options) {
+		o.dotted = true
+		o.maxDepth = maxDepth
+	}
+}
+
+// ErrSectionPath is returned for a section header that is not a valid path
+// when reading with DottedSections.
+type ErrSectionPath struct {
+	Line    int
+	Section string
+	Message string
+}
+
+func (e ErrSectionPath) Error() string {
+	return fmt.Sprintf("section [%s] on line %d: %s", e.Section, e.Line, e.Message)
+}
+
+// checkSectionPath validates a section name read on the given line according
+// to the DottedSections option.
+func (o *options) checkSectionPath(line int, name string) error {
+	if !o.dotted {
+		return nil
+	}
+	segments := strings.Split(name, ".")
+	if o.maxDepth > 0 && len(segments) > o.maxDepth {
+		return ErrSectionPath{line, name, fmt.Sprintf(
+			"%d levels, at most %d are allowed", len(segments), o.maxDepth)}
+	}
+	for i, s := range segments {
+		if s == "" {
+			return ErrSectionPath{line, name, fmt.Sprintf("segment %d is empty", i+1)}
+		}
+		if s != strings.TrimSpace(s) {
+			return ErrSectionPath{line, name, fmt.Sprintf(
+				"segment %q has surrounding white space", s)}
+		}
+	}
+	return nil
+}
+
+// Resolve looks up a key in a hierarchy of dotted sections, from the most to
+// the least specific one. For the path a, b, c it returns the value from
+// [a.b.c] if the key is there, otherwise from [a.b] and finally from [a].
+// This lets specific sections override settings of general ones:
+//
+//	[server]
+//	timeout = 30s
+//
+//	[server.upload]
+//	timeout = 5m
+//
+// Here Resolve([]string{"server", "upload"}, "timeout") returns 5m and
+// Resolve([]string{"server", "status"}, "timeout") returns 30s. The default
+// section is not part of any hierarchy and is not searched.
+func (f File) Resolve(path []string, key string) (value string, ok bool) {
+	for n := len(path); n > 0; n-- {
+		if value, ok = f.Get(strings.Join(path[:n], "."), key); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
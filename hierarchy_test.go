@@ -0,0 +1,64 @@
+package ini
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDottedSections(t *testing.T) {
+	if _, err := Read(strings.NewReader("[a.b.c]\nx = 1\n[a]"), DottedSections(3)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(strings.NewReader("[a..b]\n[a. b]")); err != nil {
+		t.Errorf("names must not be checked without DottedSections, got %v", err)
+	}
+
+	tests := []struct {
+		src   string
+		depth int
+		err   ErrSectionPath
+	}{
+		{"x = 1\n[a.b.c.d]", 3, ErrSectionPath{2, "a.b.c.d", "4 levels, at most 3 are allowed"}},
+		{"[a..b]", 0, ErrSectionPath{1, "a..b", "segment 2 is empty"}},
+		{"[.a]", 0, ErrSectionPath{1, ".a", "segment 1 is empty"}},
+		{"[a. b]", 0, ErrSectionPath{1, "a. b", `segment " b" has surrounding white space`}},
+	}
+	for _, test := range tests {
+		_, err := Read(strings.NewReader(test.src), DottedSections(test.depth))
+		if err != test.err {
+			t.Errorf("%q: expected %v, got %v", test.src, test.err, err)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	f := MustParse(`
+timeout = 1s
+[server]
+timeout = 30s
+[server.upload]
+timeout = 5m
+[server.upload.large]
+limit = 1G
+`)
+	tests := []struct {
+		path  []string
+		key   string
+		value string
+		ok    bool
+	}{
+		{[]string{"server", "upload", "large"}, "timeout", "5m", true},
+		{[]string{"server", "upload", "large"}, "limit", "1G", true},
+		{[]string{"server", "status"}, "timeout", "30s", true},
+		{[]string{"server"}, "limit", "", false},
+		{[]string{"client"}, "timeout", "", false},
+		{nil, "timeout", "", false},
+	}
+	for _, test := range tests {
+		value, ok := f.Resolve(test.path, test.key)
+		if value != test.value || ok != test.ok {
+			t.Errorf("Resolve(%q, %q) = %q, %v, want %q, %v",
+				test.path, test.key, value, ok, test.value, test.ok)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package ini
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Includes enables the include directive: a key named include is replaced by
+// the contents of the files that its value names, e.g.
+//
+//	include = base.ini
+//	include = conf.d/*.ini
+//
+// Relative paths are relative to the directory of the including file, or to
+// the working directory when reading from an io.Reader. Glob patterns, see
+// filepath.Match, are expanded in sorted order and may match no files at all,
+// while a plain path must exist. Keys of an included file that come before its
+// first section header belong to the section of the include line. Including a
+// file that is already being included is an error.
+func Includes() Option {
+	return func(o *options) {
+		o.includes = true
+	}
+}
+
+// An Include is a resolved include directive, see Includes.
+type Include struct {
+	From    string   // the including file, empty for an io.Reader
+	Line    int      // the line of the include directive in From
+	Pattern string   // the value of the include directive
+	Files   []string // the included files in the order they were read
+}
+
+// isInclude reports whether n is an include directive.
+func (b *fileBuilder) isInclude(n Node) bool {
+	return b.opts.includes && n.Kind == KeyNode && n.Key == b.opts.keyName("include")
+}
+
+// include reads the files named by the include directive n into the File.
+func (b *fileBuilder) include(n Node) error {
+	pattern, path := n.Value, n.Value
+	if !filepath.IsAbs(pattern) && b.opts.baseDir != "" {
+		pattern = filepath.Join(escapeGlob(b.opts.baseDir), pattern)
+		path = filepath.Join(b.opts.baseDir, path)
+	}
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("ini: line %d: include %q: %w", n.Line, n.Value, err)
+	}
+	if len(paths) == 0 && !hasGlobMeta(n.Value) {
+		// Report the missing file like os.Open does.
+		_, err := os.Stat(path)
+		return fmt.Errorf("ini: line %d: include: %w", n.Line, err)
+	}
+
+	inc := Include{From: b.source, Line: n.Line, Pattern: n.Value, Files: paths}
+	if b.includes != nil {
+		*b.includes = append(*b.includes, inc)
+	}
+	for _, path := range paths {
+		if err := b.includeFile(path, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *fileBuilder) includeFile(path string, directive Node) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	for _, p := range b.including {
+		if p == abs {
+			return fmt.Errorf("ini: line %d: include cycle: %s is already being read", directive.Line, path)
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("ini: line %d: include: %w", directive.Line, err)
+	}
+	defer f.Close()
+	r, err := b.opts.limitReader(f, path)
+	if err != nil {
+		return fmt.Errorf("ini: line %d: include: %w", directive.Line, err)
+	}
+
+	parentOpts, parentSource := b.opts, b.source
+	opts := *b.opts
+	opts.baseDir = filepath.Dir(path)
+	b.opts, b.source = &opts, path
+	b.including = append(b.including, abs)
+	defer func() {
+		b.opts, b.source = parentOpts, parentSource
+		b.including = b.including[:len(b.including)-1]
+	}()
+
+	inHeader := false
+	_, err = scan(bufio.NewReader(r), &opts, func(n Node) error {
+		if n.Kind == SectionNode {
+			inHeader = true
+		}
+		if !inHeader {
+			n.Section = directive.Section
+		}
+		return b.add(n)
+	})
+	if err != nil {
+		return fmt.Errorf("ini: %s: %w", path, err)
+	}
+	return nil
+}
+
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// escapeGlob returns a pattern that only matches path itself, so that the
+// directory of a file may contain glob characters.
+func escapeGlob(path string) string {
+	if filepath.Separator != '\\' {
+		path = strings.ReplaceAll(path, `\`, `\\`)
+	}
+	return globMeta.Replace(path)
+}
+
+var globMeta = strings.NewReplacer("*", "[*]", "?", "[?]", "[", "[[]")
@@ -0,0 +1,107 @@
+package ini
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.ini":         "a = main\ninclude = base.ini\n[s]\ninclude = conf.d/*.ini\nz = last",
+		"base.ini":         "a = base\nb = base",
+		"conf.d/10-x.ini":  "x = 10\nz = 10\n[t]\ny = 10",
+		"conf.d/20-x.ini":  "x = 20",
+		"conf.d/other.txt": "ignored = true",
+	})
+	main := filepath.Join(dir, "main.ini")
+
+	f, err := Load(main, Includes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := File{
+		"":  {"a": "base", "b": "base"},
+		"s": {"x": "20", "z": "last"},
+		"t": {"y": "10"},
+	}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %q, got %q", expect, f)
+	}
+
+	d, err := LoadDocument(main, Includes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	includes := []Include{
+		{From: main, Line: 2, Pattern: "base.ini", Files: []string{filepath.Join(dir, "base.ini")}},
+		{From: main, Line: 4, Pattern: "conf.d/*.ini", Files: []string{
+			filepath.Join(dir, "conf.d", "10-x.ini"),
+			filepath.Join(dir, "conf.d", "20-x.ini"),
+		}},
+	}
+	if !reflect.DeepEqual(d.Includes, includes) {
+		t.Errorf("expected %v, got %v", includes, d.Includes)
+	}
+
+	f, err = Load(main)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f[""]["include"] != "base.ini" {
+		t.Error("include must be a normal key without the Includes option")
+	}
+
+	_, err = Read(strings.NewReader("include = "+filepath.Join(dir, "nothing", "*.ini")), Includes())
+	if err != nil {
+		t.Errorf("a glob without matches must not be an error, got %v", err)
+	}
+	_, err = Read(strings.NewReader("include = "+filepath.Join(dir, "missing.ini")), Includes())
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected missing file error, got %v", err)
+	}
+
+	if b, _ := d.Get("", "b"); b != "base" {
+		t.Errorf("Get must see included keys, got %q", b)
+	}
+
+	odd := filepath.Join(dir, "odd[1]")
+	writeFiles(t, odd, map[string]string{
+		"main.ini": "include = base.ini",
+		"base.ini": "a = odd",
+	})
+	f, err = Load(filepath.Join(odd, "main.ini"), Includes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f[""]["a"] != "odd" {
+		t.Errorf("glob characters in the directory must be literal, got %q", f)
+	}
+
+	writeFiles(t, dir, map[string]string{
+		"a.ini": "include = b.ini",
+		"b.ini": "include = a.ini",
+	})
+	_, err = Load(filepath.Join(dir, "a.ini"), Includes())
+	if err == nil || !strings.Contains(err.Error(), "include cycle: "+filepath.Join(dir, "a.ini")) {
+		t.Errorf("expected include cycle, got %v", err)
+	}
+}
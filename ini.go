@@ -3,17 +3,20 @@ package ini
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
-	"os"
+	"io/ioutil"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
-var (
-	sectionRegex = regexp.MustCompile(`^\[(.*)\]$`)
-	assignRegex  = regexp.MustCompile(`^([^=]+)=(.*)$`)
-)
+var sectionRegex = regexp.MustCompile(`^\[(.*)\]$`)
 
 // ErrSyntax is returned when there is a syntax error in an INI file.
 type ErrSyntax struct {
@@ -25,6 +28,69 @@ func (e ErrSyntax) Error() string {
 	return fmt.Sprintf("invalid INI syntax on line %d: %s", e.Line, e.Source)
 }
 
+// ErrNotText is returned when the input is not a text file, e.g. because the
+// loader was pointed at an image or executable. This is detected by a null byte
+// anywhere in the input or by mostly control characters and invalid UTF-8 at
+// its start.
+type ErrNotText struct {
+	Line   int
+	Offset int64 // byte offset of the first binary byte from the start of the input
+}
+
+func (e ErrNotText) Error() string {
+	return fmt.Sprintf("input is not text: binary data on line %d at offset %d", e.Line, e.Offset)
+}
+
+// ErrLineTooLong is returned for a line longer than the limit set with
+// MaxLineLength.
+type ErrLineTooLong struct {
+	Line  int
+	Limit int
+}
+
+func (e ErrLineTooLong) Error() string {
+	return fmt.Sprintf("line %d is longer than %d bytes", e.Line, e.Limit)
+}
+
+// ErrDuplicate is returned for a repeated key in a section when reading with
+// OnDuplicate(DuplicateError).
+type ErrDuplicate struct {
+	Line    int
+	Section string
+	Key     string
+}
+
+func (e ErrDuplicate) Error() string {
+	return fmt.Sprintf("duplicate key %q in section %q on line %d", e.Key, e.Section, e.Line)
+}
+
+// ErrEmptyValue is returned for a key without a value when reading with the
+// NoEmptyValues option.
+type ErrEmptyValue struct {
+	Line    int
+	Section string
+	Key     string
+}
+
+func (e ErrEmptyValue) Error() string {
+	return fmt.Sprintf("empty value for key %q in section %q on line %d", e.Key, e.Section, e.Line)
+}
+
+// ErrSectionNotAllowed is returned for a section header when reading, or for a
+// named section when writing, with the NoSections option. Line is 0 when
+// writing.
+type ErrSectionNotAllowed struct {
+	Line    int
+	Section string
+}
+
+func (e ErrSectionNotAllowed) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("section [%s] not allowed in a file without sections", e.Section)
+	}
+	return fmt.Sprintf("section [%s] on line %d not allowed in a file without sections", e.Section, e.Line)
+}
+
 // A File represents a parsed INI file.
 type File map[string]Section
 
@@ -43,73 +109,581 @@ func (f File) Section(name string) Section {
 }
 
 // Get looks up a value for a key in a section and returns that value, along
-// with a boolean result similar to a map lookup.
+// with a boolean result similar to a map lookup. Keys that the File does not
+// contain have their default, see SetDefaults.
 func (f File) Get(section, key string) (value string, ok bool) {
 	if s := f[section]; s != nil {
 		value, ok = s[key]
 	}
-	return
+	if !ok {
+		return f.defaultValue(section, key)
+	}
+	return value, ok
+}
+
+// GetAll returns all values of a key in a section, in the order in which they
+// appear in the file. Repeated keys keep all of their values when reading with
+// OnDuplicate(DuplicateCollect), otherwise the result is the value of the key.
+// It returns nil if the key does not exist.
+func (f File) GetAll(section, key string) []string {
+	return f[section].GetAll(key)
+}
+
+// GetAll returns all values of a key in the section, see File.GetAll. Setting
+// the key to a new value replaces all values that were read.
+func (s Section) GetAll(key string) []string {
+	value, ok := s[key]
+	if !ok {
+		return nil
+	}
+	if state := stateOf(s, false); state != nil {
+		state.Lock()
+		all := state.values[key]
+		state.Unlock()
+		if len(all) > 0 && all[len(all)-1] == value {
+			return append([]string(nil), all...)
+		}
+	}
+	return []string{value}
+}
+
+// Set stores a value for a key in a section. The section is created if it does
+// not already exist. See Audit for recording changes and KeepOrder for where
+// new keys are written, which Set records in the Order of the File if it has
+// one.
+func (f File) Set(section, key, value string, opts ...Option) {
+	s := f.Section(section)
+	old, existed := s[key]
+	s[key] = value
+	o := makeOptions(opts)
+	if order := o.orderFor(f); order != nil {
+		order.addKey(section, key)
+	}
+	o.audit(AuditRecord{
+		Op:      "set",
+		Section: section,
+		Key:     key,
+		Old:     old,
+		New:     value,
+		Existed: existed,
+	})
+}
+
+// Merge copies all sections and values from other into f. Values in other
+// replace existing values in f.
+func (f File) Merge(other File) {
+	for name, section := range other {
+		target := f.Section(name)
+		for key, value := range section {
+			target[key] = value
+		}
+		mergeState(target, section)
+	}
+}
+
+// unseal returns the plain contents of r for the Encrypted and Signed
+// options, it decrypts r and verifies and removes the signature.
+func (o *options) unseal(r io.Reader) (io.Reader, error) {
+	if o.cipher == nil && o.signKey == nil {
+		return r, nil
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if o.cipher != nil {
+		data, err = o.cipher.Decrypt(data)
+		if err != nil {
+			return nil, fmt.Errorf("ini: decrypt: %w", err)
+		}
+	}
+	if o.signKey != nil {
+		data, err = verifySignature(o.signKey, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bytes.NewReader(data), nil
 }
 
 // Read loads a File from a Reader.
-func Read(r io.Reader) (File, error) {
+func Read(r io.Reader, opts ...Option) (File, error) {
+	return read(r, makeOptions(opts))
+}
+
+func read(r io.Reader, opts *options) (File, error) {
 	f := make(File)
+	opts = opts.withBudget()
+	r, err := opts.limitReader(r, opts.source)
+	if err != nil {
+		return f, err
+	}
+	r, err = opts.unseal(r)
+	if err != nil {
+		return f, err
+	}
 	bufin, ok := r.(*bufio.Reader)
 	if !ok {
-		bufin = bufio.NewReader(r)
+		if opts.bufferSize > 0 {
+			bufin = bufio.NewReaderSize(r, opts.bufferSize)
+		} else {
+			bufin = bufio.NewReader(r)
+		}
+	}
+	fileOpts := opts.fileOptions()
+	err = parseFile(bufin, f, fileOpts)
+	if err == nil {
+		err = finishFile(f, fileOpts, opts)
 	}
-	err := parseFile(bufin, f)
 	return f, err
 }
 
-// Load reads an INI File from a file on disk.
-func Load(path string) (File, error) {
-	f, err := os.Open(path)
+// fileOptions returns opts for reading a single file. The values of repeated
+// keys and the origins of all keys are recorded separately for every file, so
+// that interpolation only expands those of this file, see finishFile.
+func (opts *options) fileOptions() *options {
+	copied := *opts
+	copied.origins = make(Origins)
+	if opts.newOrder {
+		copied.order = new(Order)
+	}
+	if opts.collect != nil || opts.duplicates == DuplicateCollect {
+		copied.collect = make(MultiValues)
+	}
+	return &copied
+}
+
+// finishFile interpolates the values of f, which was read with opts from
+// fileOptions, attaches the values of repeated keys and the origins to its
+// sections and the Order to f and adds them to the MultiValues and Origins of
+// the caller.
+func finishFile(f File, opts, caller *options) error {
+	if opts.interpolation != NoInterpolation {
+		if err := interpolate(f, opts); err != nil {
+			return err
+		}
+	}
+	attachValues(f, opts.collect)
+	attachOrigins(f, opts.origins)
+	opts.orderFor(f)
+	if caller.collect != nil {
+		for section, keys := range opts.collect {
+			for key, values := range keys {
+				for _, v := range values {
+					caller.collect.add(section, key, v)
+				}
+			}
+		}
+	}
+	if caller.origins != nil {
+		for section, keys := range opts.origins {
+			for key, origin := range keys {
+				caller.origins.set(section, key, origin)
+			}
+		}
+	}
+	return nil
+}
+
+// MustParse parses src as an INI file and panics if it is invalid. It is meant
+// for tests and examples that build Files from string literals.
+func MustParse(src string, opts ...Option) File {
+	f, err := Read(strings.NewReader(src), opts...)
 	if err != nil {
-		return nil, err
+		panic(err)
+	}
+	return f
+}
+
+// Load reads an INI File from a file on disk.
+func Load(path string, opts ...Option) (File, error) {
+	return LoadSource(FileSource(path), opts...)
+}
+
+func parseFile(r *bufio.Reader, file File, opts *options) error {
+	start := time.Now()
+	b := newFileBuilder(file, opts)
+	info, err := scan(r, opts, b.add)
+	if opts.stats != nil {
+		*opts.stats = b.stats
+		opts.stats.Lines = info.lines
+		opts.stats.Bytes = info.bytes
+		opts.stats.Duration = time.Since(start)
 	}
-	defer f.Close()
-	return Read(f)
+	return err
+}
+
+// A fileBuilder adds the key and section nodes of a file to a File.
+type fileBuilder struct {
+	file  File
+	opts  *options
+	stats ParseStats
+
+	source    string     // the file being read, see Include.From
+	including []string   // absolute paths of the files being included
+	includes  *[]Include // if not nil, resolved includes are appended
+
+	aliased map[[2]string]Node // key nodes for aliases by section and key
 }
 
-func parseFile(r *bufio.Reader, file File) (err error) {
+func newFileBuilder(file File, opts *options) *fileBuilder {
+	opts = opts.withBudget()
+	b := &fileBuilder{file: file, opts: opts, source: opts.source}
+	if abs, err := filepath.Abs(opts.source); err == nil && opts.source != "" {
+		b.including = []string{abs}
+	}
+	return b
+}
+
+func (b *fileBuilder) add(n Node) error {
+	switch n.Kind {
+	case BlankNode:
+		b.stats.BlankLines++
+	case CommentNode:
+		b.stats.Comments++
+	case SectionNode:
+		b.stats.Sections++
+		// Create the section if it does not exist
+		b.file.Section(n.Section)
+		if b.opts.order != nil {
+			b.opts.order.addSection(n.Section)
+		}
+	case KeyNode:
+		if b.isInclude(n) {
+			return b.include(n)
+		}
+		b.stats.Keys++
+		if b.opts.noEmptyValues && n.Value == "" {
+			return ErrEmptyValue{n.Line, n.Section, n.Key}
+		}
+		val := n.Value
+		if b.opts.fileRefs {
+			var err error
+			if val, err = resolveFileRef(val, b.opts.baseDir); err != nil {
+				return fmt.Errorf("ini: line %d: %w", n.Line, err)
+			}
+		}
+		s := b.file.Section(n.Section)
+		if b.opts.keyAliases != nil && !b.resolveKeyAlias(&n) {
+			return nil
+		}
+		if _, exists := s[n.Key]; exists {
+			b.stats.Duplicates++
+			switch b.opts.duplicates {
+			case DuplicateKeepFirst:
+				return nil
+			case DuplicateError:
+				return ErrDuplicate{n.Line, n.Section, n.Key}
+			}
+		}
+		s[n.Key] = val
+		b.recordKey(n, val)
+	}
+	return nil
+}
+
+func (b *fileBuilder) recordKey(n Node, value string) {
+	if b.opts.collect != nil {
+		b.opts.collect.add(n.Section, n.Key, value)
+	}
+	if b.opts.order != nil {
+		b.opts.order.addKey(n.Section, n.Key)
+	}
+	if b.opts.origins != nil {
+		b.opts.origins.set(n.Section, n.Key, b.source)
+	}
+}
+
+// scanInfo describes a scanned file.
+type scanInfo struct {
+	finalNewline bool // the last line ends in a line break
+	crlf         bool // the first line ends in \r\n
+	bom          bool // the file starts with a UTF-8 byte order mark
+	lines        int
+	bytes        int64
+}
+
+// utf8BOM is the byte order mark that some editors, e.g. Notepad, write at
+// the start of UTF-8 files. It is not part of the first line.
+const utf8BOM = "\uFEFF"
+
+// scan reads r line by line and calls emit for every blank line, comment,
+// section header and key in order. Continued lines are part of the node of
+// the line they continue.
+func scan(r *bufio.Reader, opts *options, emit func(Node) error) (info scanInfo, err error) {
 	section := ""
 	lineNum := 0
+	var offset int64
+	delimiters, comments := opts.delimiterChars(), opts.commentChars()
+	// pending collects lines ending in a backslash, see BackslashContinuation.
+	pending, pendingRaw, pendingLine := "", "", 0
+	// key is the last key node, it is only emitted once the next line shows
+	// that it is not continued, see IndentContinuation. keyIndent is the
+	// indentation of its line.
+	var key *Node
+	keyIndent := 0
+
+	flushKey := func() error {
+		if key == nil {
+			return nil
+		}
+		n := *key
+		key = nil
+		return emit(n)
+	}
+
+	if sample, err := r.Peek(textSampleSize); len(sample) > 0 {
+		truncated := err == nil || err == bufio.ErrBufferFull
+		if bad := firstBinaryByte(sample, truncated, opts.latin1); bad != -1 {
+			line := 1 + bytes.Count(sample[:bad], []byte{'\n'})
+			return info, ErrNotText{line, int64(bad)}
+		}
+	}
+
 	for done := false; !done; {
 		var line string
-		if line, err = r.ReadString('\n'); err != nil {
+		if line, err = readLine(r, opts.maxLineLength); err != nil {
+			if err == errLineTooLong {
+				return info, ErrLineTooLong{lineNum + 1, opts.maxLineLength}
+			}
 			if err == io.EOF {
 				done = true
 			} else {
 				return
 			}
 		}
+		if line == "" && done {
+			break
+		}
+		if lineNum == 0 && strings.HasPrefix(line, utf8BOM) {
+			line = line[len(utf8BOM):]
+			offset += int64(len(utf8BOM))
+			info.bom = true
+		}
 		lineNum++
+		if i := strings.IndexByte(line, 0); i != -1 {
+			return info, ErrNotText{lineNum, offset + int64(i)}
+		}
+		offset += int64(len(line))
+		info.lines, info.bytes = lineNum, offset
+		info.finalNewline = strings.HasSuffix(line, "\n")
+		if lineNum == 1 {
+			info.crlf = strings.HasSuffix(line, "\r\n")
+		}
+		// Invalid UTF-8 is replaced so that all keys and values are valid
+		// strings.
+		if opts.latin1 {
+			line = latin1ToUTF8(line)
+		} else {
+			line = strings.ToValidUTF8(line, "\uFFFD")
+		}
+		raw := strings.TrimRight(line, "\r\n")
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
 		line = strings.TrimSpace(line)
+		if opts.continuation == BackslashContinuation {
+			if strings.HasSuffix(line, `\`) && !done {
+				if pendingRaw == "" {
+					pendingLine = lineNum
+				}
+				pending += strings.TrimSpace(strings.TrimSuffix(line, `\`)) + " "
+				pendingRaw += raw + "\n"
+				continue
+			}
+			if pendingRaw != "" {
+				line = strings.TrimSpace(pending + line)
+				raw = pendingRaw + raw
+				pending, pendingRaw = "", ""
+			} else {
+				pendingLine = lineNum
+			}
+		} else {
+			pendingLine = lineNum
+		}
+
 		if len(line) == 0 {
-			// Skip blank lines
+			// Blank lines end multi-line values
+			if err = flushKey(); err != nil {
+				return
+			}
+			if err = emit(Node{Kind: BlankNode, Line: lineNum, Raw: raw, Section: section}); err != nil {
+				return
+			}
+			continue
+		}
+		if strings.IndexByte(comments, line[0]) != -1 {
+			if key != nil && opts.continuation == IndentContinuation {
+				// Comments within a multi-line value belong to its node
+				key.Raw += "\n" + raw
+				continue
+			}
+			text := strings.TrimSpace(line[1:])
+			if err = emit(Node{Kind: CommentNode, Line: lineNum, Raw: raw, Section: section, Value: text}); err != nil {
+				return
+			}
 			continue
 		}
-		if line[0] == ';' || line[0] == '#' {
-			// Skip comments
+		if opts.continuation == IndentContinuation && key != nil && indent > keyIndent {
+			if opts.unicodeEscapes {
+				line = decodeUnicodeEscapes(line)
+			}
+			key.Raw += "\n" + raw
+			key.Value += "\n" + line
 			continue
 		}
+		if err = flushKey(); err != nil {
+			return
+		}
 
-		if groups := assignRegex.FindStringSubmatch(line); groups != nil {
-			key, val := groups[1], groups[2]
-			key, val = strings.TrimSpace(key), strings.TrimSpace(val)
-			file.Section(section)[key] = val
+		if i := strings.IndexAny(line, delimiters); i > 0 {
+			k, v := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+			key = &Node{Kind: KeyNode, Line: pendingLine, Raw: raw, Section: section}
+			key.Key, key.Value = opts.keyName(k), v
+			if !strings.Contains(raw, "\n") {
+				// Remember where the value is for Document.Set.
+				start := len(raw) - len(strings.TrimLeftFunc(raw, unicode.IsSpace)) + i + 1
+				start += len(raw[start:]) - len(strings.TrimLeftFunc(raw[start:], unicode.IsSpace))
+				key.valueStart, key.valueEnd = start, start+len(v)
+			}
+			if opts.quotes {
+				key.Value, key.Quote = unquote(v)
+			}
+			if opts.unicodeEscapes {
+				key.Value = decodeUnicodeEscapes(key.Value)
+			}
+			keyIndent = indent
 		} else if groups := sectionRegex.FindStringSubmatch(line); groups != nil {
 			name := strings.TrimSpace(groups[1])
+			if opts.unicodeEscapes {
+				name = decodeUnicodeEscapes(name)
+			}
+			if opts.lowerSections {
+				name = strings.ToLower(name)
+			}
+			if opts.noSections {
+				return info, ErrSectionNotAllowed{lineNum, name}
+			}
+			if err = opts.checkSectionPath(lineNum, name); err != nil {
+				return
+			}
 			section = name
-			// Create the section if it does not exist
-			file.Section(section)
+			if err = emit(Node{Kind: SectionNode, Line: pendingLine, Raw: raw, Section: section}); err != nil {
+				return
+			}
+		} else if opts.flagKeys && line[0] != '[' {
+			// A bare key is a flag with an empty value
+			key = &Node{Kind: KeyNode, Line: pendingLine, Raw: raw, Section: section, Key: opts.keyName(line)}
+			keyIndent = indent
 		} else {
-			return ErrSyntax{lineNum, line}
+			return info, ErrSyntax{lineNum, line}
+		}
+		if opts.continuation != IndentContinuation {
+			// Only indented lines continue keys and the next line can be
+			// handled for itself.
+			if err = flushKey(); err != nil {
+				return
+			}
 		}
+	}
+	return info, flushKey()
+}
+
+// textSampleSize is the number of bytes at the start of the input that are
+// checked to be text.
+const textSampleSize = 1024
 
+// firstBinaryByte returns the offset of the first byte in sample which is not
+// text, if more than a third of the sample is not text, or -1 otherwise.
+// Control characters other than whitespace and invalid UTF-8 are not text,
+// unless latin1 is set, which makes all bytes outside of ASCII text. If the
+// sample is truncated, a character cut off at its end does not count.
+func firstBinaryByte(sample []byte, truncated, latin1 bool) int {
+	first, bad := -1, 0
+	for i := 0; i < len(sample); {
+		if truncated && !utf8.FullRune(sample[i:]) {
+			sample = sample[:i]
+			break
+		}
+		r, size := utf8.DecodeRune(sample[i:])
+		isText := r != utf8.RuneError || size != 1 || latin1
+		if r < 0x20 && r != '\t' && r != '\n' && r != '\r' && r != '\f' && r != '\v' {
+			isText = false
+		}
+		if !isText {
+			bad += size
+			if first == -1 {
+				first = i
+			}
+		}
+		i += size
 	}
-	return nil
+	if bad*3 > len(sample) {
+		return first
+	}
+	return -1
+}
+
+// latin1ToUTF8 decodes all bytes of s which are not part of valid UTF-8 as
+// ISO-8859-1, see Latin1Fallback.
+func latin1ToUTF8(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			// Latin-1 bytes are the first 256 Unicode code points.
+			r = rune(s[i])
+		}
+		b.WriteRune(r)
+		i += size
+	}
+	return b.String()
+}
+
+var errLineTooLong = errors.New("line too long")
+
+// readLine reads the next line, including the line break, like ReadString. If
+// limit is positive, it returns errLineTooLong for lines longer than limit
+// bytes, not counting the line break.
+func readLine(r *bufio.Reader, limit int) (string, error) {
+	if limit <= 0 {
+		return r.ReadString('\n')
+	}
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(bytes.TrimRight(line, "\r\n")) > limit {
+			return "", errLineTooLong
+		}
+		if err != bufio.ErrBufferFull {
+			return string(line), err
+		}
+	}
+}
+
+// resolveFileRef returns the contents of the file that val refers to if it
+// starts with @, see FileReferences.
+func resolveFileRef(val, dir string) (string, error) {
+	if !strings.HasPrefix(val, "@") {
+		return val, nil
+	}
+	if strings.HasPrefix(val, "@@") {
+		return val[1:], nil
+	}
+	path := val[1:]
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	contents := string(data)
+	contents = strings.TrimSuffix(contents, "\n")
+	contents = strings.TrimSuffix(contents, "\r")
+	return contents, nil
 }
 
 //func (f File) load(r io.Reader) (err error) {
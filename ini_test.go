@@ -1,9 +1,15 @@
 package ini
 
 import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestLoad(t *testing.T) {
@@ -63,6 +69,151 @@ func TestSyntaxError(t *testing.T) {
 	}
 }
 
+func TestBinaryInput(t *testing.T) {
+	_, err := Read(strings.NewReader("a = b\nc = \x00d"))
+	if err != (ErrNotText{Line: 2, Offset: 10}) {
+		t.Errorf("expected ErrNotText, got %v", err)
+	}
+
+	f, err := Read(strings.NewReader("[s\xff]\nk\xfe = v\xc3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := File{"s\uFFFD": {"k\uFFFD": "v\uFFFD"}}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %q, got %q", expect, f)
+	}
+}
+
+func TestLatin1Fallback(t *testing.T) {
+	src := "[Stra\xdfe]\nname = M\xfcller\nutf8 = M\u00fcller"
+	f, err := Read(strings.NewReader(src), Latin1Fallback())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := File{"Straße": {"name": "Müller", "utf8": "Müller"}}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %q, got %q", expect, f)
+	}
+
+	// Text that is mostly Latin-1 is not binary with the option.
+	latin := strings.Repeat("\xe4\xf6\xfc", 100)
+	if _, err := Read(strings.NewReader("k = "+latin), Latin1Fallback()); err != nil {
+		t.Errorf("expected Latin-1 text to be accepted, got %v", err)
+	}
+	if _, err := Read(strings.NewReader("k = " + latin)); err == nil {
+		t.Error("expected mostly invalid UTF-8 to be rejected without the option")
+	}
+}
+
+func TestStats(t *testing.T) {
+	src := "; comment\n\na = 1\n[s]\nb = 2\nb = 3\n[s]\n# another\nc = 4\n"
+	var stats ParseStats
+	if _, err := Read(strings.NewReader(src), Stats(&stats)); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Duration < 0 {
+		t.Error("expected a non-negative parse duration")
+	}
+	stats.Duration = 0
+	expect := ParseStats{
+		Lines:      9,
+		Bytes:      int64(len(src)),
+		BlankLines: 1,
+		Comments:   2,
+		Sections:   2,
+		Keys:       4,
+		Duplicates: 1,
+	}
+	if stats != expect {
+		t.Errorf("expected %+v, got %+v", expect, stats)
+	}
+}
+
+func TestNotText(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x01\x00\x00\x00\x01\x00\x08\x06"
+	_, err := Read(strings.NewReader(png))
+	if err != (ErrNotText{Line: 1, Offset: 0}) {
+		t.Errorf("expected ErrNotText at offset 0, got %v", err)
+	}
+
+	// A few stray bytes in a text file are replaced, not rejected.
+	text := "[s]\nname = M\xfcller\n" + strings.Repeat("key = value\n", 100)
+	if _, err := Read(strings.NewReader(text), ReadBufferSize(16)); err != nil {
+		t.Errorf("expected text with a stray byte to be accepted, got %v", err)
+	}
+	// Multi-byte characters may be cut off at the end of the sample.
+	utf := strings.Repeat("ü", textSampleSize)
+	if _, err := Read(strings.NewReader("k = " + utf[1:])); err != nil {
+		t.Errorf("expected UTF-8 text to be accepted, got %v", err)
+	}
+}
+
+func TestMaxLineLength(t *testing.T) {
+	long := "key = " + strings.Repeat("x", 10000)
+	src := "a = b\r\n" + long + "\nc = d"
+
+	f, err := Read(strings.NewReader(src), ReadBufferSize(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f[""]["key"]) != 10000 {
+		t.Errorf("expected long value, got %d bytes", len(f[""]["key"]))
+	}
+
+	_, err = Read(strings.NewReader(src), MaxLineLength(len(long)-1), ReadBufferSize(16))
+	if err != (ErrLineTooLong{2, len(long) - 1}) {
+		t.Errorf("expected ErrLineTooLong, got %v", err)
+	}
+	if _, err := Read(strings.NewReader(src), MaxLineLength(len(long))); err != nil {
+		t.Errorf("expected line at the limit to be accepted, got %v", err)
+	}
+}
+
+func TestNoEmptyValues(t *testing.T) {
+	_, err := Read(strings.NewReader("a = 1\n[s]\n\nb =\nc = 3"), NoEmptyValues())
+	if err != (ErrEmptyValue{4, "s", "b"}) {
+		t.Errorf("expected ErrEmptyValue, got %v", err)
+	}
+	f, err := Read(strings.NewReader("a = 1\nb = \"\""), NoEmptyValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f[""]["b"] != `""` {
+		t.Errorf("unexpected value %q", f[""]["b"])
+	}
+	if _, err := Read(strings.NewReader("b =")); err != nil {
+		t.Errorf("empty values must be allowed by default, got %v", err)
+	}
+}
+
+func FuzzRead(f *testing.F) {
+	f.Add([]byte("[foo]\nbar = baz\n; comment\n"))
+	f.Add([]byte("a = b\r\n[ c ]\r\nd=e=f"))
+	f.Add([]byte("\xff\xfe[\x00]"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		file, err := Read(bytes.NewReader(data))
+		switch err.(type) {
+		case nil, ErrSyntax, ErrNotText:
+		default:
+			t.Fatalf("unexpected error type %T: %v", err, err)
+		}
+		if err != nil {
+			return
+		}
+		for name, section := range file {
+			if !utf8.ValidString(name) {
+				t.Errorf("invalid UTF-8 in section name %q", name)
+			}
+			for key, value := range section {
+				if !utf8.ValidString(key) || !utf8.ValidString(value) {
+					t.Errorf("invalid UTF-8 in %q = %q", key, value)
+				}
+			}
+		}
+	})
+}
+
 func TestDefinedSectionBehaviour(t *testing.T) {
 	check := func(src string, expect File) {
 		file, err := Read(strings.NewReader(src))
@@ -88,6 +239,18 @@ func TestDefinedSectionBehaviour(t *testing.T) {
 	})
 }
 
+func TestMustParse(t *testing.T) {
+	if f := MustParse("[a]\nb = c"); !reflect.DeepEqual(f, File{"a": {"b": "c"}}) {
+		t.Errorf("unexpected File %v", f)
+	}
+	defer func() {
+		if _, ok := recover().(ErrSyntax); !ok {
+			t.Error("expected panic with ErrSyntax")
+		}
+	}()
+	MustParse("wut?")
+}
+
 func TestCanLoadFile(t *testing.T) {
 	f, err := Load("./testdata/test.ini")
 	if err != nil {
@@ -97,3 +260,43 @@ func TestCanLoadFile(t *testing.T) {
 		t.Error("file not read correctly")
 	}
 }
+
+func TestFileReferences(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "secret")
+	if err := ioutil.WriteFile(secret, []byte("hunter2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "app.ini")
+	src := "password = @secret\nabsolute = @" + secret + "\nhandle = @@gonutz"
+	if err := ioutil.WriteFile(path, []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := Load(path, FileReferences())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := File{"": {
+		"password": "hunter2",
+		"absolute": "hunter2",
+		"handle":   "@gonutz",
+	}}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %v, got %v", expect, f)
+	}
+
+	// Without the option values are taken literally.
+	f, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := f.Get("", "password"); v != "@secret" {
+		t.Errorf("expected literal value, got %q", v)
+	}
+
+	_, err = Read(strings.NewReader("x = @"+filepath.Join(dir, "missing")), FileReferences())
+	if !os.IsNotExist(errors.Unwrap(err)) {
+		t.Errorf("expected not-exist error, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package ini
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InterpolationStyle specifies the syntax for references to other values.
+type InterpolationStyle int
+
+const (
+	// NoInterpolation leaves values as they are. This is the default.
+	NoInterpolation InterpolationStyle = iota
+	// DollarInterpolation replaces ${key} with the value of key in the same
+	// section, or in the default section if the same section does not have
+	// it, and ${section:key} with the value of key in section. $$ stands for a
+	// literal $.
+	DollarInterpolation
+	// PercentInterpolation replaces %(key)s with the value of key in the same
+	// section, or in the default section if the same section does not have
+	// it, like Python's configparser. %% stands for a literal %.
+	PercentInterpolation
+)
+
+// Interpolation makes reading replace references to other values, see
+// InterpolationStyle. References are resolved after the whole file was read,
+// so they can refer to keys further down. Reading fails with ErrInterpolation
+// for references to missing keys and for cycles.
+//
+// When writing, the escape character is doubled, so $ is written as $$ or % as
+// %%, and reading the output with the same option gives the same values.
+func Interpolation(style InterpolationStyle) Option {
+	return func(o *options) {
+		o.interpolation = style
+	}
+}
+
+// ErrInterpolation is returned for a value whose references can not be
+// resolved, see Interpolation.
+type ErrInterpolation struct {
+	Section   string
+	Key       string
+	Reference string // the reference as written in the value, e.g. ${a:b}
+	Message   string
+}
+
+func (e ErrInterpolation) Error() string {
+	return fmt.Sprintf("cannot interpolate %s in key %q in section %q: %s",
+		e.Reference, e.Key, e.Section, e.Message)
+}
+
+// interpolate replaces the references in all values of f.
+func interpolate(f File, opts *options) error {
+	in := &interpolator{
+		file:     f,
+		opts:     opts,
+		done:     make(map[[2]string]string),
+		visiting: make(map[[2]string]bool),
+	}
+	for _, section := range sortedKeys(f) {
+		for _, key := range sortedSectionKeys(f[section]) {
+			if _, err := in.value(section, key); err != nil {
+				return err
+			}
+		}
+	}
+	for id, value := range in.done {
+		f[id[0]][id[1]] = value
+	}
+	// All values of repeated keys are expanded, references to a repeated key
+	// use the value in the File.
+	for section, keys := range opts.collect {
+		for key, values := range keys {
+			for i, v := range values {
+				expanded, err := in.expand(section, key, v)
+				if err != nil {
+					return err
+				}
+				values[i] = expanded
+			}
+		}
+	}
+	return nil
+}
+
+type interpolator struct {
+	file     File
+	opts     *options
+	done     map[[2]string]string // section and key to expanded value
+	visiting map[[2]string]bool
+}
+
+// value returns the expanded value of a key, which must exist.
+func (in *interpolator) value(section, key string) (string, error) {
+	id := [2]string{section, key}
+	if value, ok := in.done[id]; ok {
+		return value, nil
+	}
+	in.visiting[id] = true
+	defer delete(in.visiting, id)
+
+	value, err := in.expand(section, key, in.file[section][key])
+	if err != nil {
+		return "", err
+	}
+	in.done[id] = value
+	return value, nil
+}
+
+// expand replaces the references in value, which belongs to key in section.
+func (in *interpolator) expand(section, key, value string) (string, error) {
+	escape, open, close := byte('$'), "${", "}"
+	if in.opts.interpolation == PercentInterpolation {
+		escape, open, close = '%', "%(", ")s"
+	}
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(value, escape)
+		if i == -1 || i == len(value)-1 {
+			b.WriteString(value)
+			return b.String(), nil
+		}
+		b.WriteString(value[:i])
+		value = value[i:]
+		if value[1] == escape {
+			b.WriteByte(escape)
+			value = value[2:]
+			continue
+		}
+		if !strings.HasPrefix(value, open) {
+			b.WriteByte(escape)
+			value = value[1:]
+			continue
+		}
+		end := strings.Index(value, close)
+		if end == -1 {
+			return "", ErrInterpolation{section, key, value, "missing " + close}
+		}
+		ref := value[:end+len(close)]
+		name := value[len(open):end]
+		value = value[end+len(close):]
+
+		refSection, refKey := section, name
+		if in.opts.interpolation == DollarInterpolation {
+			if colon := strings.IndexByte(name, ':'); colon != -1 {
+				refSection, refKey = strings.TrimSpace(name[:colon]), name[colon+1:]
+				if in.opts.lowerSections {
+					refSection = strings.ToLower(refSection)
+				}
+			}
+		}
+		refKey = in.opts.keyName(strings.TrimSpace(refKey))
+		if _, ok := in.file[refSection][refKey]; !ok && refSection == section {
+			refSection = ""
+		}
+		if _, ok := in.file[refSection][refKey]; !ok {
+			return "", ErrInterpolation{section, key, ref, "key not found"}
+		}
+		id := [2]string{refSection, refKey}
+		if in.visiting[id] {
+			return "", ErrInterpolation{section, key, ref, "reference cycle"}
+		}
+		expanded, err := in.value(refSection, refKey)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(expanded)
+	}
+}
+
+// escapeInterpolation doubles the escape character of style in s, so that
+// reading it with Interpolation(style) gives s again.
+func escapeInterpolation(s string, style InterpolationStyle) string {
+	switch style {
+	case DollarInterpolation:
+		return strings.Replace(s, "$", "$$", -1)
+	case PercentInterpolation:
+		return strings.Replace(s, "%", "%%", -1)
+	}
+	return s
+}
@@ -0,0 +1,82 @@
+package ini
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInterpolation(t *testing.T) {
+	check := func(src string, expect File, opts ...Option) {
+		t.Helper()
+		f, err := Read(strings.NewReader(src), opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(f, expect) {
+			t.Errorf("expected %q, got %q", expect, f)
+		}
+	}
+	dollar, percent := Interpolation(DollarInterpolation), Interpolation(PercentInterpolation)
+
+	check("home = /home/me\n[app]\ndata = ${home}/${dir}\ndir = app\nprice = $$5 or $ 6\ncopy = ${other:x}\n[other]\nx = $${data}",
+		File{
+			"":      {"home": "/home/me"},
+			"app":   {"data": "/home/me/app", "dir": "app", "price": "$5 or $ 6", "copy": "${data}"},
+			"other": {"x": "${data}"},
+		}, dollar)
+	check("[a]\nx = 1\n[b]\ny = ${a:x}", File{"a": {"x": "1"}, "b": {"y": "1"}}, dollar)
+	check("dir = /tmp\nfile = %(dir)s/x\nrate = 100%%\nliteral = ${dir}",
+		File{"": {"dir": "/tmp", "file": "/tmp/x", "rate": "100%", "literal": "${dir}"}}, percent)
+	check("[S]\nKey = v\nref = ${s:KEY}", File{"s": {"key": "v", "ref": "v"}},
+		dollar, CaseInsensitiveSections(), CaseInsensitiveKeys())
+	check("a = $${x}", File{"": {"a": "$${x}"}})
+
+	for src, expect := range map[string]ErrInterpolation{
+		"a = ${b}":           {"", "a", "${b}", "key not found"},
+		"a = ${b}\nb = ${a}": {"", "b", "${a}", "reference cycle"},
+		"a = ${a":            {"", "a", "${a", "missing }"},
+		"[s]\na = ${t:a}":    {"s", "a", "${t:a}", "key not found"},
+	} {
+		_, err := Read(strings.NewReader(src), dollar)
+		if err != expect {
+			t.Errorf("%q: expected %v, got %v", src, expect, err)
+		}
+	}
+}
+
+func TestInterpolationWrite(t *testing.T) {
+	f := File{"": {"price": "$5", "ref": "${x}", "rate": "5%"}}
+	for _, style := range []InterpolationStyle{DollarInterpolation, PercentInterpolation} {
+		var buf strings.Builder
+		if err := f.Write(&buf, Interpolation(style)); err != nil {
+			t.Fatal(err)
+		}
+		back, err := Read(strings.NewReader(buf.String()), Interpolation(style))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(back, f) {
+			t.Errorf("style %d: expected %q, got %q from\n%s", style, f, back, buf.String())
+		}
+	}
+}
+
+func TestInterpolationCollected(t *testing.T) {
+	values := make(MultiValues)
+	opts := []Option{Interpolation(DollarInterpolation), CollectValues(values)}
+	for _, src := range []string{"dir = /srv\npath = ${dir}/a\npath = ${dir}/b", "cost = $$1"} {
+		if _, err := Read(strings.NewReader(src), opts...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if all := values.Get("", "path"); !reflect.DeepEqual(all, []string{"/srv/a", "/srv/b"}) {
+		t.Errorf("unexpected values %q", all)
+	}
+	if _, err := Read(strings.NewReader("x = 1"), opts...); err != nil {
+		t.Fatal(err)
+	}
+	if all := values.Get("", "cost"); !reflect.DeepEqual(all, []string{"$1"}) {
+		t.Errorf("values of earlier files must not be expanded again, got %q", all)
+	}
+}
@@ -0,0 +1,150 @@
+package ini
+
+import "os"
+
+// A Layer is one source of configuration values in Layers.
+type Layer struct {
+	Name string // describes the source, e.g. the path that File was loaded from
+	File File
+}
+
+// Layers combine several Files of increasing precedence into one
+// configuration. Reads see the merged values, with values from later layers
+// overriding those from earlier ones. Writes go to the last layer only, which
+// Save writes to SavePath.
+//
+// The typical use is an administrator's system-wide configuration that users
+// may override, see LoadSystemUser.
+type Layers struct {
+	Stack    []Layer // in order of increasing precedence
+	SavePath string
+}
+
+// LoadSystemUser loads the system configuration at systemPath and the user
+// configuration at userPath into two Layers, the user's having precedence.
+// Changes are saved to userPath. Missing files result in empty layers.
+func LoadSystemUser(systemPath, userPath string, opts ...Option) (*Layers, error) {
+	l := &Layers{SavePath: userPath}
+	for _, path := range []string{systemPath, userPath} {
+		f, err := Load(path, opts...)
+		if os.IsNotExist(err) {
+			f, err = make(File), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		l.Stack = append(l.Stack, Layer{Name: path, File: f})
+	}
+	return l, nil
+}
+
+// Get returns the value for a key in a section from the layer with the highest
+// precedence that contains it.
+func (l *Layers) Get(section, key string) (value string, ok bool) {
+	for i := len(l.Stack) - 1; i >= 0; i-- {
+		if value, ok = l.Stack[i].File.Get(section, key); ok {
+			return
+		}
+	}
+	return "", false
+}
+
+// Origin returns the Name of the layer that the value for a key in a section
+// comes from, see Get. It returns false if no layer contains the key.
+func (l *Layers) Origin(section, key string) (name string, ok bool) {
+	for i := len(l.Stack) - 1; i >= 0; i-- {
+		if _, ok = l.Stack[i].File.Get(section, key); ok {
+			return l.Stack[i].Name, true
+		}
+	}
+	return "", false
+}
+
+// Origins returns the Name of the layer that every value of File comes from.
+// Write the File with AnnotateOrigins(l.Origins()) to see which layer sets
+// what.
+func (l *Layers) Origins() Origins {
+	origins := make(Origins)
+	for _, layer := range l.Stack {
+		for section, keys := range layer.File {
+			for key := range keys {
+				origins.set(section, key, layer.Name)
+			}
+		}
+	}
+	return origins
+}
+
+// Set stores a value for a key in a section in the top layer. If there are no
+// layers, one named after SavePath is added.
+func (l *Layers) Set(section, key, value string, opts ...Option) {
+	l.top().Set(section, key, value, opts...)
+}
+
+// Save writes the top layer to SavePath.
+func (l *Layers) Save(opts ...Option) error {
+	return l.top().Save(l.SavePath, opts...)
+}
+
+// File returns a new File with the merged values of all layers.
+func (l *Layers) File() File {
+	merged := make(File)
+	for _, layer := range l.Stack {
+		merged.Merge(layer.File)
+	}
+	return merged
+}
+
+// DefaultsLayer is the Name of the layer added by SetDefaults.
+const DefaultsLayer = "defaults"
+
+// SetDefaults registers default values that Get returns for keys which no
+// other layer contains. They map section names to keys to values. The defaults
+// are the layer with the lowest precedence, named DefaultsLayer, and
+// replace those of an earlier call. They are never saved. Unlike
+// File.SetDefaults, the defaults are a layer of their own and show up in
+// Origins and File.
+func (l *Layers) SetDefaults(defaults map[string]map[string]string) {
+	f := make(File)
+	for section, keys := range defaults {
+		for key, value := range keys {
+			f.Section(section)[key] = value
+		}
+	}
+	layer := Layer{Name: DefaultsLayer, File: f}
+	if len(l.Stack) > 0 && l.Stack[0].Name == DefaultsLayer {
+		l.Stack[0] = layer
+	} else {
+		l.Stack = append([]Layer{layer}, l.Stack...)
+	}
+	if len(l.Stack) == 1 {
+		// Set must not write to the defaults.
+		l.Stack = append(l.Stack, Layer{Name: l.SavePath, File: make(File)})
+	}
+}
+
+// WithDefaults returns Layers that read values from f and fall back to the
+// given defaults, see SetDefaults. Set modifies f.
+func WithDefaults(f File, defaults map[string]map[string]string) *Layers {
+	l := &Layers{Stack: []Layer{{File: f}}}
+	l.SetDefaults(defaults)
+	return l
+}
+
+// IsDefault reports whether the value that Get returns for a key in a section
+// is a default registered with SetDefaults rather than set explicitly.
+func (l *Layers) IsDefault(section, key string) bool {
+	for i := len(l.Stack) - 1; i >= 0; i-- {
+		if _, ok := l.Stack[i].File.Get(section, key); ok {
+			return i == 0 && l.Stack[0].Name == DefaultsLayer
+		}
+	}
+	return false
+}
+
+func (l *Layers) top() File {
+	if len(l.Stack) == 0 {
+		l.Stack = append(l.Stack, Layer{Name: l.SavePath, File: make(File)})
+	}
+	return l.Stack[len(l.Stack)-1].File
+}
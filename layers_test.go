@@ -0,0 +1,95 @@
+package ini
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadSystemUser(t *testing.T) {
+	dir := t.TempDir()
+	systemPath := filepath.Join(dir, "system.ini")
+	userPath := filepath.Join(dir, "user.ini")
+	src := "[net]\nproxy = none\ntimeout = 30"
+	if err := ioutil.WriteFile(systemPath, []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := LoadSystemUser(systemPath, userPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Set("net", "timeout", "5")
+	if v, _ := l.Get("net", "timeout"); v != "5" {
+		t.Errorf("expected user override, got %q", v)
+	}
+	if v, _ := l.Get("net", "proxy"); v != "none" {
+		t.Errorf("expected system value, got %q", v)
+	}
+	if _, ok := l.Get("net", "missing"); ok {
+		t.Error("expected missing key")
+	}
+	if name, _ := l.Origin("net", "timeout"); name != userPath {
+		t.Errorf("expected timeout from user layer, got %q", name)
+	}
+	if name, _ := l.Origin("net", "proxy"); name != systemPath {
+		t.Errorf("expected proxy from system layer, got %q", name)
+	}
+	if _, ok := l.Origin("net", "missing"); ok {
+		t.Error("expected no origin for missing key")
+	}
+	expect := File{"net": {"proxy": "none", "timeout": "5"}}
+	if merged := l.File(); !reflect.DeepEqual(merged, expect) {
+		t.Errorf("expected merged %v, got %v", expect, merged)
+	}
+
+	if err := l.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if user, err := Load(userPath); err != nil || !reflect.DeepEqual(user, File{"net": {"timeout": "5"}}) {
+		t.Errorf("expected only user values saved, got %v, %v", user, err)
+	}
+	if system, _ := Load(systemPath); !reflect.DeepEqual(system, MustParse(src)) {
+		t.Errorf("expected system file unchanged, got %v", system)
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	f := MustParse("[net]\ntimeout = 5")
+	l := WithDefaults(f, map[string]map[string]string{
+		"net": {"timeout": "30", "retries": "3"},
+	})
+	if v, _ := l.Get("net", "timeout"); v != "5" {
+		t.Errorf("expected explicit value, got %q", v)
+	}
+	if v, _ := l.Get("net", "retries"); v != "3" {
+		t.Errorf("expected default value, got %q", v)
+	}
+	if l.IsDefault("net", "timeout") || !l.IsDefault("net", "retries") || l.IsDefault("net", "missing") {
+		t.Error("wrong IsDefault results")
+	}
+
+	l.Set("net", "retries", "1")
+	if f["net"]["retries"] != "1" || l.IsDefault("net", "retries") {
+		t.Error("Set must write to the file, not the defaults")
+	}
+
+	l.SetDefaults(map[string]map[string]string{"log": {"level": "info"}})
+	if len(l.Stack) != 2 {
+		t.Errorf("expected defaults to be replaced, got %d layers", len(l.Stack))
+	}
+	if _, ok := l.Get("net", "timeout"); !ok {
+		t.Error("expected explicit value to remain")
+	}
+	if v, _ := l.Get("log", "level"); v != "info" {
+		t.Errorf("expected new default, got %q", v)
+	}
+
+	empty := &Layers{}
+	empty.SetDefaults(map[string]map[string]string{"a": {"b": "c"}})
+	empty.Set("a", "b", "d")
+	if empty.Stack[0].File["a"]["b"] != "c" {
+		t.Error("Set modified the defaults")
+	}
+}
@@ -0,0 +1,111 @@
+package ini
+
+import (
+	"fmt"
+	"io"
+)
+
+// MaxFiles limits the number of files that one call reads to n: the file
+// itself plus all files it includes, see Includes, or the files of a
+// directory for LoadDir. Reading fails with ErrTooManyFiles when the limit is
+// exceeded. This protects services from configurations that include far more
+// files than intended, by accident or on purpose.
+func MaxFiles(n int) Option {
+	return func(o *options) {
+		o.maxFiles = n
+	}
+}
+
+// MaxTotalBytes limits the number of bytes that one call reads to n, counting
+// all included files, see MaxFiles. Reading fails with ErrTooMuchData when the
+// limit is exceeded.
+func MaxTotalBytes(n int64) Option {
+	return func(o *options) {
+		o.maxTotalBytes = n
+	}
+}
+
+// ErrTooManyFiles is returned when reading more files than allowed with
+// MaxFiles.
+type ErrTooManyFiles struct {
+	Limit int
+	Path  string // the file that would have exceeded the limit
+}
+
+func (e ErrTooManyFiles) Error() string {
+	return fmt.Sprintf("ini: reading %s exceeds the limit of %d files", e.Path, e.Limit)
+}
+
+// ErrTooMuchData is returned when reading more bytes than allowed with
+// MaxTotalBytes.
+type ErrTooMuchData struct {
+	Limit int64
+	Path  string // the file that exceeded the limit, empty for an io.Reader
+}
+
+func (e ErrTooMuchData) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("ini: input exceeds the limit of %d bytes", e.Limit)
+	}
+	return fmt.Sprintf("ini: reading %s exceeds the limit of %d bytes", e.Path, e.Limit)
+}
+
+// readBudget counts the files and bytes read by one call, see MaxFiles and
+// MaxTotalBytes.
+type readBudget struct {
+	files int
+	bytes int64
+}
+
+// limitReader counts a new file of the given name against the limits and
+// returns a reader that fails once the byte limit is exceeded. The options
+// must be those of the current call, with a budget shared by the files read
+// for it.
+func (o *options) limitReader(r io.Reader, name string) (io.Reader, error) {
+	if o.maxFiles <= 0 && o.maxTotalBytes <= 0 {
+		return r, nil
+	}
+	o.budget.files++
+	if o.maxFiles > 0 && o.budget.files > o.maxFiles {
+		return nil, ErrTooManyFiles{o.maxFiles, name}
+	}
+	if o.maxTotalBytes <= 0 {
+		return r, nil
+	}
+	return &budgetReader{r: r, opts: o, name: name}, nil
+}
+
+// withBudget returns o with a new budget for a call if there are limits and
+// the call did not get one from its caller, like files of LoadDir do.
+func (o *options) withBudget() *options {
+	if o.budget != nil || o.maxFiles <= 0 && o.maxTotalBytes <= 0 {
+		return o
+	}
+	withBudget := *o
+	withBudget.budget = &readBudget{}
+	return &withBudget
+}
+
+// sharedBudget returns an Option that makes several calls share one budget.
+func sharedBudget() Option {
+	b := &readBudget{}
+	return func(o *options) {
+		o.budget = b
+	}
+}
+
+type budgetReader struct {
+	r    io.Reader
+	opts *options
+	name string
+}
+
+func (r *budgetReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.opts.budget.bytes += int64(n)
+	if r.opts.budget.bytes > r.opts.maxTotalBytes {
+		// Drop the data so the error is reported before any of it is used.
+		return 0, ErrTooMuchData{r.opts.maxTotalBytes, r.name}
+	}
+	return n, err
+}
@@ -0,0 +1,64 @@
+package ini
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.ini":  "include = inc/*.ini\na = " + strings.Repeat("x", 100),
+		"inc/1.ini": "b = 1",
+		"inc/2.ini": "c = 2",
+		"d/10.ini":  "x = " + strings.Repeat("y", 100),
+		"d/20.ini":  "y = 1",
+	})
+	main := filepath.Join(dir, "main.ini")
+
+	if _, err := Load(main, Includes(), MaxFiles(3), MaxTotalBytes(200)); err != nil {
+		t.Errorf("expected limits to be met, got %v", err)
+	}
+
+	_, err := Load(main, Includes(), MaxFiles(2))
+	var files ErrTooManyFiles
+	if !errors.As(err, &files) || files.Limit != 2 || files.Path != filepath.Join(dir, "inc", "2.ini") {
+		t.Errorf("expected ErrTooManyFiles, got %v", err)
+	}
+
+	_, err = Load(main, Includes(), MaxTotalBytes(50))
+	var data ErrTooMuchData
+	if !errors.As(err, &data) || data.Limit != 50 || data.Path != main {
+		t.Errorf("expected ErrTooMuchData, got %v", err)
+	}
+
+	_, err = LoadDocument(main, Includes(), MaxFiles(1))
+	if !errors.As(err, &files) {
+		t.Errorf("expected ErrTooManyFiles for a Document, got %v", err)
+	}
+
+	_, err = Read(strings.NewReader(strings.Repeat("a = b\n", 100)), MaxTotalBytes(10))
+	if err != (ErrTooMuchData{10, ""}) {
+		t.Errorf("expected ErrTooMuchData for a reader, got %v", err)
+	}
+
+	if _, err := LoadDir(filepath.Join(dir, "d"), MaxFiles(2), MaxTotalBytes(120)); err != nil {
+		t.Errorf("expected directory within limits, got %v", err)
+	}
+	if _, err := LoadDir(filepath.Join(dir, "d"), MaxFiles(1)); !errors.As(err, &files) {
+		t.Errorf("expected ErrTooManyFiles for LoadDir, got %v", err)
+	}
+	if _, err := LoadDir(filepath.Join(dir, "d"), MaxTotalBytes(105)); !errors.As(err, &data) {
+		t.Errorf("expected ErrTooMuchData for LoadDir, got %v", err)
+	}
+
+	// Every call has its own budget.
+	p := NewParser(MaxFiles(1))
+	for i := 0; i < 3; i++ {
+		if _, err := p.Read(strings.NewReader("a = 1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
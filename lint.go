@@ -0,0 +1,165 @@
+package ini
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A LintIssue is a problem in an INI file found by Lint. The file may still be
+// valid, but the issue is likely a mistake or causes trouble with other
+// programs reading it.
+type LintIssue struct {
+	Line    int
+	Rule    string // short name of the rule, e.g. "trailing-whitespace"
+	Message string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("line %d: %s (%s)", i.Line, i.Message, i.Rule)
+}
+
+// Lint rules, see Lint.
+const (
+	// LintIndentation reports indentation with tabs in a file that is
+	// otherwise indented with spaces, or vice versa, or lines mixing both.
+	LintIndentation = "indentation"
+	// LintTrailingWhitespace reports whitespace at the end of lines, including
+	// lines of only whitespace. This package ignores it but other programs
+	// make it part of the value.
+	LintTrailingWhitespace = "trailing-whitespace"
+	// LintNonBreakingSpace reports non-breaking spaces, which are usually
+	// pasted from documents by accident and look like normal spaces.
+	LintNonBreakingSpace = "non-breaking-space"
+)
+
+// nonBreakingSpaces are replaced with normal spaces by Format.
+var nonBreakingSpaces = strings.NewReplacer("\u00A0", " ", "\u2007", " ", "\u202F", " ")
+
+// Lint checks the INI file read from r for whitespace problems. All issues
+// that Lint reports are fixed by Format. Directive comments turn off rules for
+// parts of the file, see DirectiveLintDisable.
+func Lint(r io.Reader) ([]LintIssue, error) {
+	lines, _, err := readRawLines(r)
+	if err != nil {
+		return nil, err
+	}
+	var issues []LintIssue
+	var directives lintSuppression
+	add := func(line int, rule, msg string) {
+		if !directives.disabled(rule) {
+			issues = append(issues, LintIssue{line, rule, msg})
+		}
+	}
+
+	indent := dominantIndent(lines)
+	for i, line := range lines {
+		n := i + 1
+		directives.advance(line)
+		lead := leadingWhitespace(line)
+		switch {
+		case lead == line:
+			// Blank lines have no indentation.
+		case strings.Contains(lead, " ") && strings.Contains(lead, "\t"):
+			add(n, LintIndentation, "indentation mixes tabs and spaces")
+		case lead != "" && lead[0] != indent:
+			add(n, LintIndentation, fmt.Sprintf("indented with %s, the file uses %s", indentName(lead[0]), indentName(indent)))
+		}
+		if strings.TrimRight(line, " \t") != line {
+			if strings.TrimSpace(line) == "" {
+				add(n, LintTrailingWhitespace, "whitespace on a blank line")
+			} else {
+				add(n, LintTrailingWhitespace, "trailing whitespace")
+			}
+		}
+		if nonBreakingSpaces.Replace(line) != line {
+			add(n, LintNonBreakingSpace, "non-breaking space")
+		}
+	}
+	return issues, nil
+}
+
+// Format reads an INI file from r and writes it to w with all issues reported
+// by Lint fixed: non-breaking spaces become normal spaces, trailing whitespace
+// is removed and indentation uses the character that most of the file is
+// indented with. Everything else, including comments, the order of lines and
+// line breaks, is kept. Lines after an "; ini:keep" comment are not changed at
+// all.
+func Format(r io.Reader, w io.Writer) error {
+	lines, breaks, err := readRawLines(r)
+	if err != nil {
+		return err
+	}
+	indent := dominantIndent(lines)
+	bufout := bufio.NewWriter(w)
+	var directives lintSuppression
+	for i, line := range lines {
+		directives.advance(line)
+		if directives.keep() {
+			bufout.WriteString(line + breaks[i])
+			continue
+		}
+		line = nonBreakingSpaces.Replace(line)
+		line = strings.TrimRight(line, " \t")
+		// A tab counts as four spaces.
+		lead := leadingWhitespace(line)
+		width := len(lead) + 3*strings.Count(lead, "\t")
+		if indent == '\t' {
+			lead = strings.Repeat("\t", (width+3)/4)
+		} else {
+			lead = strings.Repeat(" ", width)
+		}
+		line = lead + strings.TrimLeft(line, " \t")
+		bufout.WriteString(line + breaks[i])
+	}
+	return bufout.Flush()
+}
+
+// readRawLines returns all lines of r without their line breaks, and the line
+// breaks, "\n", "\r\n" or "" for a last line without one.
+func readRawLines(r io.Reader) (lines, breaks []string, err error) {
+	bufin := bufio.NewReader(r)
+	for {
+		line, err := bufin.ReadString('\n')
+		if line != "" {
+			text := strings.TrimRight(line, "\r\n")
+			lines = append(lines, text)
+			breaks = append(breaks, line[len(text):])
+		}
+		if err == io.EOF {
+			return lines, breaks, nil
+		}
+		if err != nil {
+			return lines, breaks, err
+		}
+	}
+}
+
+// dominantIndent returns the character, tab or space, that starts the most
+// indented lines. It is a space if no line is indented.
+func dominantIndent(lines []string) byte {
+	tabs, spaces := 0, 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "\t") {
+			tabs++
+		} else if strings.HasPrefix(line, " ") && strings.TrimSpace(line) != "" {
+			spaces++
+		}
+	}
+	if tabs > spaces {
+		return '\t'
+	}
+	return ' '
+}
+
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+func indentName(c byte) string {
+	if c == '\t' {
+		return "tabs"
+	}
+	return "spaces"
+}
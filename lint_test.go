@@ -0,0 +1,71 @@
+package ini
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	src := "[server]\n" +
+		"  host = a\n" +
+		"\tport = 80\n" +
+		" \tuser = x\n" +
+		"  name = trailing \n" +
+		"  title = a\u00A0b\n" +
+		"  \n"
+	issues, err := Lint(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []LintIssue{
+		{3, LintIndentation, "indented with tabs, the file uses spaces"},
+		{4, LintIndentation, "indentation mixes tabs and spaces"},
+		{5, LintTrailingWhitespace, "trailing whitespace"},
+		{6, LintNonBreakingSpace, "non-breaking space"},
+		{7, LintTrailingWhitespace, "whitespace on a blank line"},
+	}
+	if !reflect.DeepEqual(issues, expect) {
+		t.Errorf("expected %v, got %v", expect, issues)
+	}
+
+	var buf bytes.Buffer
+	if err := Format(strings.NewReader(src), &buf); err != nil {
+		t.Fatal(err)
+	}
+	formatted := "[server]\n" +
+		"  host = a\n" +
+		"    port = 80\n" +
+		"     user = x\n" +
+		"  name = trailing\n" +
+		"  title = a b\n" +
+		"\n"
+	if buf.String() != formatted {
+		t.Errorf("expected\n%q\ngot\n%q", formatted, buf.String())
+	}
+	if issues, _ := Lint(&buf); len(issues) != 0 {
+		t.Errorf("expected no issues after Format, got %v", issues)
+	}
+}
+
+func TestFormatTabs(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Format(strings.NewReader("[a]\n\tx = 1\n\ty = 2\n      z = 3"), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "[a]\n\tx = 1\n\ty = 2\n\t\tz = 3"; buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
+
+func TestFormatLineBreaks(t *testing.T) {
+	var buf bytes.Buffer
+	src := "[a] \r\nx = 1\r\n; ini:keep\r\ny = 2 \r\nz = 3\n"
+	if err := Format(strings.NewReader(src), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "[a]\r\nx = 1\r\n; ini:keep\r\ny = 2 \r\nz = 3\n"; buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
@@ -0,0 +1,105 @@
+package ini
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ReadMulti reads a stream of several INI documents, each ending at a line
+// that consists only of separator, e.g. "---". Blank text before the first and
+// after the last separator does not count as a document. Line numbers and
+// offsets in errors count from the start of the stream. The separator must
+// not be empty or blank. MaxFiles and MaxTotalBytes limit the whole stream,
+// every document counts as a file.
+func ReadMulti(r io.Reader, separator string, opts ...Option) ([]File, error) {
+	if strings.TrimSpace(separator) == "" {
+		return nil, errors.New("ini: ReadMulti needs a separator that is not blank")
+	}
+	o := makeOptions(opts).withBudget()
+	var docs []string
+	// The line number before and the byte offset of the start of each
+	// document.
+	var docLines []int
+	var docOffsets []int64
+	var doc strings.Builder
+	start, lineNum := 0, 0
+	var startOffset, offset int64
+
+	bufin := bufio.NewReader(r)
+	for done := false; !done; {
+		line, err := readLine(bufin, o.maxLineLength)
+		if err == errLineTooLong {
+			return nil, ErrLineTooLong{lineNum + 1, o.maxLineLength}
+		}
+		if err == io.EOF {
+			done = true
+		} else if err != nil {
+			return nil, err
+		}
+		if line == "" && done {
+			break
+		}
+		lineNum++
+		offset += int64(len(line))
+		if strings.TrimSpace(line) == separator {
+			docs = append(docs, doc.String())
+			docLines = append(docLines, start)
+			docOffsets = append(docOffsets, startOffset)
+			doc.Reset()
+			start, startOffset = lineNum, offset
+			continue
+		}
+		doc.WriteString(line)
+	}
+	docs = append(docs, doc.String())
+	docLines = append(docLines, start)
+	docOffsets = append(docOffsets, startOffset)
+
+	if strings.TrimSpace(docs[0]) == "" {
+		docs, docLines, docOffsets = docs[1:], docLines[1:], docOffsets[1:]
+	}
+	if n := len(docs); n > 0 && strings.TrimSpace(docs[n-1]) == "" {
+		docs, docLines, docOffsets = docs[:n-1], docLines[:n-1], docOffsets[:n-1]
+	}
+
+	files := make([]File, 0, len(docs))
+	for i, doc := range docs {
+		f, err := read(strings.NewReader(doc), o)
+		if err != nil {
+			return files, offsetPosition(err, docLines[i], docOffsets[i])
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// offsetPosition adds lines and bytes to the position of parse errors.
+func offsetPosition(err error, lines int, bytes int64) error {
+	switch e := err.(type) {
+	case ErrSyntax:
+		e.Line += lines
+		return e
+	case ErrDuplicate:
+		e.Line += lines
+		return e
+	case ErrEmptyValue:
+		e.Line += lines
+		return e
+	case ErrSectionNotAllowed:
+		e.Line += lines
+		return e
+	case ErrLineTooLong:
+		e.Line += lines
+		return e
+	case ErrSectionPath:
+		e.Line += lines
+		return e
+	case ErrNotText:
+		e.Line += lines
+		e.Offset += bytes
+		return e
+	}
+	return err
+}
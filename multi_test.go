@@ -0,0 +1,87 @@
+package ini
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadMulti(t *testing.T) {
+	src := `---
+[a]
+x = 1
+---
+---
+[b]
+y = 2
+---
+`
+	files, err := ReadMulti(strings.NewReader(src), "---")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []File{
+		{"a": {"x": "1"}},
+		{},
+		{"b": {"y": "2"}},
+	}
+	if !reflect.DeepEqual(files, expect) {
+		t.Errorf("expected %v, got %v", expect, files)
+	}
+
+	_, err = ReadMulti(strings.NewReader("a = 1\n---\nb = 2\nwut?"), "---")
+	if err != (ErrSyntax{4, "wut?"}) {
+		t.Errorf("expected syntax error on line 4, got %v", err)
+	}
+
+	_, err = ReadMulti(strings.NewReader("a = 1\n---\nb = \x00"), "---")
+	if err != (ErrNotText{3, 14}) {
+		t.Errorf("expected binary input error at offset 14, got %v", err)
+	}
+
+	files, err = ReadMulti(strings.NewReader(""), "---")
+	if err != nil || len(files) != 0 {
+		t.Errorf("expected no documents, got %v, %v", files, err)
+	}
+}
+
+func TestReadMultiErrorLines(t *testing.T) {
+	const stream = "a = 1\nb = 2\n---\n"
+	tests := []struct {
+		doc  string
+		opts []Option
+		err  error
+	}{
+		{"c = 3\nd =\n", []Option{NoEmptyValues()}, ErrEmptyValue{5, "", "d"}},
+		{"c = 3\n[s]\n", []Option{NoSections()}, ErrSectionNotAllowed{5, "s"}},
+		{"c = 3\nd = 12345678\n", []Option{MaxLineLength(10)}, ErrLineTooLong{5, 10}},
+		{"c = 3\n[a..b]\n", []Option{DottedSections(0)}, ErrSectionPath{5, "a..b", "segment 2 is empty"}},
+	}
+	for _, test := range tests {
+		_, err := ReadMulti(strings.NewReader(stream+test.doc), "---", test.opts...)
+		if err != test.err {
+			t.Errorf("%q: expected %v, got %v", test.doc, test.err, err)
+		}
+	}
+}
+
+func TestReadMultiLimits(t *testing.T) {
+	const stream = "a = 1\n---\nb = 2\n---\nc = 3\n"
+	_, err := ReadMulti(strings.NewReader(stream), "---", MaxFiles(2))
+	if _, ok := err.(ErrTooManyFiles); !ok {
+		t.Errorf("expected ErrTooManyFiles, got %v", err)
+	}
+	_, err = ReadMulti(strings.NewReader(stream), "---", MaxTotalBytes(15))
+	if _, ok := err.(ErrTooMuchData); !ok {
+		t.Errorf("expected ErrTooMuchData, got %v", err)
+	}
+	if files, err := ReadMulti(strings.NewReader(stream), "---", MaxFiles(3), MaxTotalBytes(18)); err != nil || len(files) != 3 {
+		t.Errorf("expected 3 documents within the limits, got %v, %v", files, err)
+	}
+
+	for _, separator := range []string{"", "  "} {
+		if _, err := ReadMulti(strings.NewReader("a = 1\n\nb = 2"), separator); err == nil {
+			t.Errorf("expected an error for separator %q", separator)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package ini
+
+import (
+	"strings"
+	"time"
+)
+
+// An Option changes how INI files are read or how values are interpreted by the
+// typed getters. Options that do not apply to a function are ignored by it.
+type Option func(*options)
+
+type options struct {
+	fileRefs       bool
+	baseDir        string // directory that file references are relative to
+	localeNumbers  bool
+	flagKeys       bool
+	emptyBool      EmptyBool
+	auditWho       string
+	auditLog       func(AuditRecord)
+	signKey        []byte
+	cipher         Cipher
+	delimiters     string
+	comments       string
+	lowerSections  bool
+	lowerKeys      bool
+	duplicates     DuplicatePolicy
+	continuation   ContinuationStyle
+	unicodeEscapes bool
+	escapeNonASCII bool
+	noSections     bool
+	dotted         bool
+	maxDepth       int
+	noEmptyValues  bool
+	interpolation  InterpolationStyle
+	includes       bool
+	origins        Origins
+	order          *Order
+	newOrder       bool // KeepOrder(nil), every File gets a new Order
+	collect        MultiValues
+	annotate       Origins
+	anyKeys        bool // write keys that checkKey rejects, see SupportDump
+	quotes         bool
+	warn           func(Warning)
+	keyAliases     map[[2]string]string // section and alias to key
+	maxFiles       int
+	maxTotalBytes  int64
+	budget         *readBudget
+	boolStyle      BoolStyle
+	intBase        int
+	floatFormat    byte
+	floatPrecision int
+	source         string // path of the file being read, for Include.From
+	maxLineLength  int
+	bufferSize     int
+	latin1         bool
+	stats          *ParseStats
+}
+
+func makeOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// FileReferences enables file indirection: a value starting with @ is replaced
+// by the contents of the file it names, e.g.
+//
+//	password = @/run/secrets/db_password
+//
+// Relative paths are resolved against the directory of the INI file when using
+// Load and against the working directory when using Read. A single trailing
+// line break is removed from the file contents. Start a value with @@ to get a
+// literal value starting with @.
+func FileReferences() Option {
+	return func(o *options) {
+		o.fileRefs = true
+	}
+}
+
+// LocaleNumbers makes GetInt and GetFloat accept numbers as they are commonly
+// written outside the English speaking world: digits may be grouped with
+// spaces, thin spaces or underscores (1 000 000) and GetFloat accepts a comma
+// as the decimal separator (3,14).
+func LocaleNumbers() Option {
+	return func(o *options) {
+		o.localeNumbers = true
+	}
+}
+
+// FlagKeys allows lines consisting of only a key, without an equals sign. Such
+// flag-style keys are stored with an empty value, just like "key =". Use
+// EmptyBoolAs to specify what GetBool returns for them.
+func FlagKeys() Option {
+	return func(o *options) {
+		o.flagKeys = true
+	}
+}
+
+// EmptyBool specifies how GetBool interprets a key with an empty value.
+type EmptyBool int
+
+const (
+	// EmptyInvalid makes GetBool return an error for empty values. This is the
+	// default.
+	EmptyInvalid EmptyBool = iota
+	// EmptyTrue makes GetBool return true for empty values.
+	EmptyTrue
+	// EmptyFalse makes GetBool return false for empty values.
+	EmptyFalse
+	// EmptyUnset makes GetBool treat empty values as if the key did not exist.
+	EmptyUnset
+)
+
+// EmptyBoolAs sets how GetBool interprets a key with an empty value, e.g. a
+// flag-style key, see FlagKeys.
+func EmptyBoolAs(b EmptyBool) Option {
+	return func(o *options) {
+		o.emptyBool = b
+	}
+}
+
+// NoSections is for flat files of only key = value lines, like .env files.
+// Reading fails with ErrSectionNotAllowed at the first section header, so all
+// keys are in the default section. Writing fails with ErrSectionNotAllowed if
+// the File has any named sections.
+func NoSections() Option {
+	return func(o *options) {
+		o.noSections = true
+	}
+}
+
+// NoEmptyValues makes reading fail with ErrEmptyValue for a key without a
+// value, like "key =", for configurations where an empty value is always a
+// mistake. This includes bare keys read with FlagKeys.
+func NoEmptyValues() Option {
+	return func(o *options) {
+		o.noEmptyValues = true
+	}
+}
+
+// MaxLineLength limits the length of lines, not counting the line break, to n
+// bytes. Reading fails with ErrLineTooLong at the first longer line. By default
+// lines can be of any length, this option protects against inputs that would
+// otherwise be read into memory in one piece.
+func MaxLineLength(n int) Option {
+	return func(o *options) {
+		o.maxLineLength = n
+	}
+}
+
+// ReadBufferSize sets the size of the buffer used to read the input. The
+// default is 4096 bytes. Lines longer than the buffer are still read fine, a
+// larger buffer only makes reading files with very long lines, e.g. embedded
+// PEM blocks, more efficient.
+func ReadBufferSize(n int) Option {
+	return func(o *options) {
+		o.bufferSize = n
+	}
+}
+
+// Latin1Fallback decodes bytes that are not valid UTF-8 as ISO-8859-1
+// (Latin-1), the historical encoding of INI and properties files on many
+// systems. Without this option such bytes are replaced with U+FFFD. Valid
+// UTF-8 is decoded as UTF-8 either way, so files that mix both are read
+// correctly.
+func Latin1Fallback() Option {
+	return func(o *options) {
+		o.latin1 = true
+	}
+}
+
+// ParseStats describe a parsed file, see Stats.
+type ParseStats struct {
+	Lines      int   // physical lines read
+	Bytes      int64 // bytes read
+	BlankLines int
+	Comments   int // comment lines
+	Sections   int // section headers, a section may have several
+	Keys       int // key lines, including duplicates
+	Duplicates int // keys that appeared again in the same section
+	Duration   time.Duration
+}
+
+// Stats makes Read and Load fill in s with statistics about the parsed file,
+// e.g. to export them as metrics. Statistics are also filled in when parsing
+// fails, describing the input up to the error.
+func Stats(s *ParseStats) Option {
+	return func(o *options) {
+		o.stats = s
+	}
+}
+
+// keyName returns the name under which key is stored.
+func (o *options) keyName(key string) string {
+	if o.unicodeEscapes {
+		key = decodeUnicodeEscapes(key)
+	}
+	if o.lowerKeys {
+		key = strings.ToLower(key)
+	}
+	return key
+}
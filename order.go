@@ -0,0 +1,161 @@
+package ini
+
+// An Order remembers the order in which sections and keys first appeared in
+// the files read with KeepOrder. The zero value is an empty Order.
+type Order struct {
+	sections []string
+	keys     map[string][]string
+	known    map[[2]string]bool
+}
+
+// KeepOrder makes SectionNames, Keys and writing list sections and keys in the
+// order recorded in o instead of sorted. Reading with this option records the
+// order of the file in o, Set with this option appends new keys to it. Both
+// also make o the Order of the File, if it has none yet, which these functions
+// then use without the option. If o is nil, every File gets a new Order, e.g.
+//
+//	f, err := ini.Load(path, ini.KeepOrder(nil))
+//	...
+//	f.Set("server", "timeout", "30s")
+//	err = f.Save(path)
+//
+// Sections and keys of the File that are not in o come after those that are,
+// in sorted order. The default section always comes first. Copying sections
+// into another File, e.g. with Merge, does not copy the Order, and
+// Section.Write only uses the option since a Section has no File.
+func KeepOrder(o *Order) Option {
+	return func(opts *options) {
+		opts.order = o
+		opts.newOrder = o == nil
+	}
+}
+
+// orderOf returns the Order of f, see KeepOrder.
+func orderOf(f File) *Order {
+	state := stateOf(f, false)
+	if state == nil {
+		return nil
+	}
+	state.Lock()
+	defer state.Unlock()
+	return state.order
+}
+
+// orderFor returns the Order that Set and reading record keys in: the one
+// given with KeepOrder, or else the Order of f. An Order given with KeepOrder
+// becomes the Order of f if it has none. It returns nil if there is neither.
+func (opts *options) orderFor(f File) *Order {
+	if opts.order == nil && !opts.newOrder {
+		return orderOf(f)
+	}
+	state := stateOf(f, true)
+	if state == nil {
+		return opts.order
+	}
+	state.Lock()
+	defer state.Unlock()
+	if state.order == nil {
+		state.order = opts.order
+		if state.order == nil {
+			state.order = new(Order)
+		}
+	}
+	if opts.order != nil {
+		return opts.order
+	}
+	return state.order
+}
+
+// withOrderOf returns opts for listing the sections and keys of f, with the
+// Order of f unless KeepOrder gives one.
+func (opts *options) withOrderOf(f File) *options {
+	if opts.order != nil {
+		return opts
+	}
+	order := orderOf(f)
+	if order == nil {
+		return opts
+	}
+	copied := *opts
+	copied.order = order
+	return &copied
+}
+
+func (o *Order) addSection(section string) {
+	if o.known == nil {
+		o.known = make(map[[2]string]bool)
+		o.keys = make(map[string][]string)
+	}
+	id := [2]string{section, ""}
+	if !o.known[id] {
+		o.known[id] = true
+		o.sections = append(o.sections, section)
+	}
+}
+
+func (o *Order) addKey(section, key string) {
+	o.addSection(section)
+	id := [2]string{section, "=" + key}
+	if !o.known[id] {
+		o.known[id] = true
+		o.keys[section] = append(o.keys[section], key)
+	}
+}
+
+// SectionNames returns the names of all sections of the File, including the
+// default section if it exists. They are sorted unless KeepOrder is given or
+// the File has an Order, the default section "" always comes first.
+func (f File) SectionNames(opts ...Option) []string {
+	return makeOptions(opts).withOrderOf(f).sectionNames(f)
+}
+
+// Keys returns the keys of a section, sorted unless KeepOrder is given or the
+// File has an Order. It returns nil if the section does not exist.
+func (f File) Keys(section string, opts ...Option) []string {
+	s, ok := f[section]
+	if !ok {
+		return nil
+	}
+	return makeOptions(opts).withOrderOf(f).keys(section, s)
+}
+
+func (opts *options) sectionNames(f File) []string {
+	names := sortedKeys(f)
+	if opts.order == nil {
+		return names
+	}
+	ordered := make([]string, 0, len(names))
+	if _, ok := f[""]; ok {
+		ordered = append(ordered, "")
+	}
+	for _, name := range opts.order.sections {
+		if _, ok := f[name]; ok && name != "" {
+			ordered = append(ordered, name)
+		}
+	}
+	for _, name := range names {
+		if !opts.order.known[[2]string{name, ""}] && name != "" {
+			ordered = append(ordered, name)
+		}
+	}
+	return ordered
+}
+
+func (opts *options) keys(section string, s Section) []string {
+	keys := sortedSectionKeys(s)
+	if opts.order == nil {
+		return keys
+	}
+	ordered := make([]string, 0, len(keys))
+	for _, key := range opts.order.keys[section] {
+		if _, ok := s[key]; ok {
+			ordered = append(ordered, key)
+		}
+	}
+	for _, key := range keys {
+		if !opts.order.known[[2]string{section, "=" + key}] {
+			ordered = append(ordered, key)
+		}
+	}
+	return ordered
+}
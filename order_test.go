@@ -0,0 +1,102 @@
+package ini
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKeepOrder(t *testing.T) {
+	var order Order
+	f, err := Read(strings.NewReader(`
+[server]
+port = 80
+host = localhost
+[auth]
+[database]
+user = admin
+`), KeepOrder(&order))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := f.SectionNames(KeepOrder(&order)); !reflect.DeepEqual(names, []string{"server", "auth", "database"}) {
+		t.Errorf("unexpected names %q", names)
+	}
+	if names := f.SectionNames(); !reflect.DeepEqual(names, []string{"server", "auth", "database"}) {
+		t.Errorf("expected the File to keep its order, got %q", names)
+	}
+	if names := MustParse("[b]\n[a]").SectionNames(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("expected sorted names by default, got %q", names)
+	}
+
+	f.Set("server", "timeout", "30s", KeepOrder(&order))
+	f.Set("server", "backlog", "10")
+	f.Set("", "debug", "true")
+	if keys := f.Keys("server", KeepOrder(&order)); !reflect.DeepEqual(keys, []string{"port", "host", "timeout", "backlog"}) {
+		t.Errorf("unexpected keys %q", keys)
+	}
+	plain := make(File)
+	plain.Merge(f)
+	if keys := plain.Keys("server"); !reflect.DeepEqual(keys, []string{"backlog", "host", "port", "timeout"}) {
+		t.Errorf("expected sorted keys for a copy, got %q", keys)
+	}
+	if keys := f.Keys("missing", KeepOrder(&order)); keys != nil {
+		t.Errorf("expected nil for missing section, got %q", keys)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expect := `debug = true
+
+[server]
+port = 80
+host = localhost
+timeout = 30s
+backlog = 10
+
+[auth]
+
+[database]
+user = admin
+`
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\nbut got\n%s", expect, buf.String())
+	}
+}
+
+func TestKeepNewOrder(t *testing.T) {
+	p := NewParser(KeepOrder(nil))
+	f, err := p.Read(strings.NewReader("[b]\nz = 1\ny = 2\n[a]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := p.Read(strings.NewReader("[c]\n[b]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Set("b", "x", "3")
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "[b]\nz = 1\ny = 2\nx = 3\n\n[a]\n"; buf.String() != expect {
+		t.Errorf("expected\n%s\nbut got\n%s", expect, buf.String())
+	}
+	if names := g.SectionNames(); !reflect.DeepEqual(names, []string{"c", "b"}) {
+		t.Errorf("expected every File to have its own order, got %q", names)
+	}
+
+	built := make(File)
+	built.Set("z", "b", "1", KeepOrder(nil))
+	built.Set("z", "a", "2")
+	built.Set("y", "c", "3")
+	if names := built.SectionNames(); !reflect.DeepEqual(names, []string{"z", "y"}) {
+		t.Errorf("unexpected names %q", names)
+	}
+	if keys := built.Keys("z"); !reflect.DeepEqual(keys, []string{"b", "a"}) {
+		t.Errorf("unexpected keys %q", keys)
+	}
+}
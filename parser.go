@@ -0,0 +1,87 @@
+package ini
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// A Parser reads and writes Files with a fixed set of options. The options are
+// evaluated once in NewParser instead of on every call, which matters for
+// servers that parse many files with the same settings. A Parser is safe for
+// concurrent use. Options that fill in a caller's variable, Stats,
+// RecordOrigins, KeepOrder and CollectValues, make calls take turns, since
+// they all change that one variable. Read it only when no call is running.
+type Parser struct {
+	opts options
+
+	// shared is set if the options fill in a caller's variable, mu serializes
+	// calls in that case.
+	shared bool
+	mu     sync.Mutex
+}
+
+// NewParser returns a Parser that uses the given options for all operations.
+func NewParser(opts ...Option) *Parser {
+	o := makeOptions(opts)
+	return &Parser{
+		opts:   *o,
+		shared: o.stats != nil || o.origins != nil || o.order != nil || o.collect != nil,
+	}
+}
+
+// options returns a copy of the options that a single call can modify.
+func (p *Parser) options() *options {
+	o := p.opts
+	return &o
+}
+
+// lock makes calls take turns if the options share state, see Parser. It
+// returns the function to unlock.
+func (p *Parser) lock() func() {
+	if !p.shared {
+		return func() {}
+	}
+	p.mu.Lock()
+	return p.mu.Unlock
+}
+
+// Read is like the package function Read.
+func (p *Parser) Read(r io.Reader) (File, error) {
+	defer p.lock()()
+	return read(r, p.options())
+}
+
+// Load is like the package function Load.
+func (p *Parser) Load(path string) (File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	defer p.lock()()
+	o := p.options()
+	o.baseDir = filepath.Dir(path)
+	o.source = path
+	return read(f, o)
+}
+
+// ParseDocument is like the package function ParseDocument. Later calls to
+// methods of the Document, like File, are not serialized with the Parser.
+func (p *Parser) ParseDocument(r io.Reader) (*Document, error) {
+	defer p.lock()()
+	return parseDocument(r, p.options())
+}
+
+// Write is like File.Write.
+func (p *Parser) Write(f File, w io.Writer) error {
+	defer p.lock()()
+	return f.write(w, p.options())
+}
+
+// Save is like File.Save.
+func (p *Parser) Save(f File, path string) error {
+	defer p.lock()()
+	return f.Save(path, func(o *options) { *o = p.opts })
+}
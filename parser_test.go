@@ -0,0 +1,68 @@
+package ini
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParser(t *testing.T) {
+	p := NewParser(CaseInsensitiveKeys(), Delimiters(":="))
+	f, err := p.Read(strings.NewReader("[s]\nKey: value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f, File{"s": {"key": "value"}}) {
+		t.Errorf("unexpected file %v", f)
+	}
+
+	path := filepath.Join(t.TempDir(), "x.ini")
+	if err := p.Save(File{"": {"a": "1"}}, path); err != nil {
+		t.Fatal(err)
+	}
+	if f, err := p.Load(path); err != nil || f[""]["a"] != "1" {
+		t.Errorf("unexpected result %v, %v", f, err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			src := fmt.Sprintf("[s]\nN: %d", i)
+			f, err := p.Read(strings.NewReader(src))
+			if err != nil || f["s"]["n"] != fmt.Sprint(i) {
+				t.Errorf("%d: unexpected result %v, %v", i, f, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestParserConcurrentState(t *testing.T) {
+	var order Order
+	var stats ParseStats
+	origins := make(Origins)
+	p := NewParser(KeepOrder(&order), Stats(&stats), RecordOrigins(origins))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			src := fmt.Sprintf("[s%d]\na = 1\nb = 2", i)
+			if _, err := p.Read(strings.NewReader(src)); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if n := len(order.sections); n != 8 {
+		t.Errorf("expected 8 recorded sections, got %d", n)
+	}
+	if stats.Keys != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,48 @@
+package ini
+
+// A Patch is a list of changes to a File, applied in order.
+type Patch []Change
+
+// A Change sets a key in a section to Value or, if Delete is set, removes the
+// key.
+type Change struct {
+	Section string
+	Key     string
+	Value   string
+	Delete  bool
+}
+
+// Apply makes the changes to f. Sections are created as needed. Deleting a key
+// that does not exist does nothing.
+func (p Patch) Apply(f File, opts ...Option) {
+	for _, c := range p {
+		if c.Delete {
+			delete(f[c.Section], c.Key)
+		} else {
+			f.Set(c.Section, c.Key, c.Value, opts...)
+		}
+	}
+}
+
+// Validate checks what f would look like after applying changes, without
+// modifying f. It returns the problems that schema reports for the keys that
+// the changes touch, e.g. a value of the wrong type or a required key that
+// would be deleted. Problems with other keys are not reported, so a UI can
+// reject bad edits even for files that are not valid already.
+func (f File) Validate(changes Patch, schema Schema) []Problem {
+	patched := make(File)
+	patched.Merge(f)
+	changes.Apply(patched)
+
+	touched := make(map[[2]string]bool)
+	for _, c := range changes {
+		touched[[2]string{c.Section, c.Key}] = true
+	}
+	var problems []Problem
+	for _, p := range schema.Validate(patched) {
+		if touched[[2]string{p.Section, p.Key}] {
+			problems = append(problems, p)
+		}
+	}
+	return problems
+}
@@ -0,0 +1,53 @@
+package ini
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPatchApply(t *testing.T) {
+	f := MustParse("[s]\na = 1\nb = 2")
+	Patch{
+		{Section: "s", Key: "a", Value: "10"},
+		{Section: "s", Key: "b", Delete: true},
+		{Section: "t", Key: "c", Value: "3"},
+		{Section: "missing", Key: "x", Delete: true},
+	}.Apply(f)
+	expect := File{"s": {"a": "10"}, "t": {"c": "3"}}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %v, got %v", expect, f)
+	}
+}
+
+func TestFileValidate(t *testing.T) {
+	schema := Schema{"server": {
+		"host": {Required: true},
+		"port": {Type: "int", HasRange: true, Min: 1, Max: 65535},
+		"mode": {Type: "bool"},
+	}}
+	// The existing file is invalid, mode is not a boolean.
+	f := MustParse("[server]\nhost = x\nport = 80\nmode = fast")
+
+	if problems := f.Validate(Patch{{Section: "server", Key: "port", Value: "8080"}}, schema); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+
+	problems := f.Validate(Patch{
+		{Section: "server", Key: "port", Value: "0"},
+		{Section: "server", Key: "host", Delete: true},
+	}, schema)
+	expect := []Problem{
+		{"server", "host", "required key is missing"},
+		{"server", "port", "0 is not in range [1, 65535]"},
+	}
+	if !reflect.DeepEqual(problems, expect) {
+		t.Errorf("expected %v, got %v", expect, problems)
+	}
+
+	if v := f["server"]["port"]; v != "80" {
+		t.Errorf("Validate must not modify the file, port is %q", v)
+	}
+	if _, ok := f.Get("server", "host"); !ok {
+		t.Error("Validate must not modify the file, host was deleted")
+	}
+}
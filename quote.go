@@ -0,0 +1,54 @@
+package ini
+
+import "strings"
+
+// QuotedValues makes reading remove matching double or single quotes around
+// values, so that "  padded  " keeps its spaces and "" is an explicit empty
+// value. There are no escapes inside the quotes. Writing a File quotes the
+// values that would otherwise change when read back.
+//
+// A Document read with this option remembers the quotes of every value, see
+// Node.Quote, and Document.Set keeps them, so editing a value does not change
+// the quoting style of the file.
+func QuotedValues() Option {
+	return func(o *options) {
+		o.quotes = true
+	}
+}
+
+// unquote removes matching quotes around s and returns the quote character,
+// or 0 if s is not quoted.
+func unquote(s string) (string, byte) {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], s[0]
+	}
+	return s, 0
+}
+
+// needsQuotes reports whether s would be read back differently with
+// QuotedValues if it was written without quotes.
+func needsQuotes(s string) bool {
+	_, q := unquote(s)
+	return q != 0 || s != strings.TrimSpace(s)
+}
+
+// quoteValue puts s in the given quote character, or in the other one if s
+// contains it. A quote of 0 means double quotes.
+func quoteValue(s string, quote byte) string {
+	if quote == 0 {
+		quote = '"'
+	}
+	if strings.IndexByte(s, quote) != -1 {
+		other := byte('"')
+		if quote == '"' {
+			other = '\''
+		}
+		if strings.IndexByte(s, other) == -1 {
+			quote = other
+		}
+	}
+	// A value containing both quote characters can still be quoted, only
+	// the last character matters when reading.
+	q := string(quote)
+	return q + s + q
+}
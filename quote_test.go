@@ -0,0 +1,64 @@
+package ini
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestQuotedValues(t *testing.T) {
+	src := "a = \"  padded  \"\nb = 'single'\nc = \"\"\nd = bare\ne = \"unbalanced"
+	f, err := Read(strings.NewReader(src), QuotedValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := File{"": {"a": "  padded  ", "b": "single", "c": "", "d": "bare", "e": `"unbalanced`}}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %q, got %q", expect, f)
+	}
+	if f, _ := Read(strings.NewReader(src)); f[""]["b"] != "'single'" {
+		t.Error("quotes must be kept without the option")
+	}
+
+	var buf strings.Builder
+	if err := f.Write(&buf, QuotedValues()); err != nil {
+		t.Fatal(err)
+	}
+	back, err := Read(strings.NewReader(buf.String()), QuotedValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, f) {
+		t.Errorf("round trip changed values:\n%s", buf.String())
+	}
+}
+
+func TestDocumentKeepsQuotes(t *testing.T) {
+	src := "a = \"x\"\nb = 'y'  \nc = z\nd = \"\"\n"
+	d, err := ParseDocument(strings.NewReader(src), QuotedValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set("", "a", "new")
+	d.Set("", "b", "it's")
+	d.Set("", "c", " spaced ")
+	d.Set("", "d", "filled")
+	d.Set("", "e", "")
+	d.Set("", "f", "'q'")
+
+	var buf strings.Builder
+	d.WriteTo(&buf)
+	expect := "a = \"new\"\nb = \"it's\"  \nc = \" spaced \"\nd = \"filled\"\ne =\nf = \"'q'\"\n"
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, buf.String())
+	}
+	back, err := ParseDocument(strings.NewReader(buf.String()), QuotedValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[string]string{"a": "new", "b": "it's", "c": " spaced ", "d": "filled", "f": "'q'"} {
+		if v, _ := back.Get("", key); v != value {
+			t.Errorf("%s: expected %q, got %q", key, value, v)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package ini
+
+import "fmt"
+
+// ErrReadOnlySection is returned for changes to a section that was marked as
+// read-only, see File.Protect.
+type ErrReadOnlySection struct {
+	Section string
+}
+
+func (e ErrReadOnlySection) Error() string {
+	return fmt.Sprintf("ini: section [%s] is read-only", e.Section)
+}
+
+// A ProtectedFile gives access to a File in which some sections are
+// read-only, see File.Protect.
+type ProtectedFile struct {
+	File     File
+	readOnly map[string]bool
+}
+
+// Protect returns a ProtectedFile for f in which the given sections can not be
+// changed, so that applications sharing a file only modify their own parts of
+// it, e.g. not the [system] section. Changes through the ProtectedFile go to f.
+// Protect can be called on a ProtectedFile's File again to protect more
+// sections, the ProtectedFiles are independent of each other.
+func (f File) Protect(sections ...string) *ProtectedFile {
+	p := &ProtectedFile{File: f, readOnly: make(map[string]bool)}
+	for _, s := range sections {
+		p.readOnly[s] = true
+	}
+	return p
+}
+
+// ReadOnly reports whether a section is read-only.
+func (p *ProtectedFile) ReadOnly(section string) bool {
+	return p.readOnly[section]
+}
+
+// Get looks up a value like File.Get.
+func (p *ProtectedFile) Get(section, key string) (value string, ok bool) {
+	return p.File.Get(section, key)
+}
+
+// Set stores a value like File.Set, unless the section is read-only.
+func (p *ProtectedFile) Set(section, key, value string, opts ...Option) error {
+	if p.readOnly[section] {
+		return ErrReadOnlySection{section}
+	}
+	p.File.Set(section, key, value, opts...)
+	return nil
+}
+
+// Delete removes a key from a section, unless the section is read-only.
+func (p *ProtectedFile) Delete(section, key string) error {
+	if p.readOnly[section] {
+		return ErrReadOnlySection{section}
+	}
+	delete(p.File[section], key)
+	return nil
+}
+
+// Apply applies the changes of a Patch, see Patch.Apply. If any of them
+// affect a read-only section, none are applied.
+func (p *ProtectedFile) Apply(changes Patch, opts ...Option) error {
+	for _, c := range changes {
+		if p.readOnly[c.Section] {
+			return ErrReadOnlySection{c.Section}
+		}
+	}
+	changes.Apply(p.File, opts...)
+	return nil
+}
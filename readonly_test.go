@@ -0,0 +1,39 @@
+package ini
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProtect(t *testing.T) {
+	f := MustParse("[system]\nroot = /\n[app]\ntheme = dark")
+	p := f.Protect("system")
+
+	if err := p.Set("system", "root", "/tmp"); err != (ErrReadOnlySection{"system"}) {
+		t.Errorf("expected ErrReadOnlySection, got %v", err)
+	}
+	if err := p.Delete("system", "root"); err != (ErrReadOnlySection{"system"}) {
+		t.Errorf("expected ErrReadOnlySection, got %v", err)
+	}
+	if err := p.Set("app", "theme", "light"); err != nil {
+		t.Error(err)
+	}
+	if err := p.Delete("app", "missing"); err != nil {
+		t.Error(err)
+	}
+	err := p.Apply(Patch{{Section: "app", Key: "font", Value: "mono"}, {Section: "system", Key: "x", Delete: true}})
+	if err != (ErrReadOnlySection{"system"}) {
+		t.Errorf("expected ErrReadOnlySection, got %v", err)
+	}
+	if err := p.Apply(Patch{{Section: "new", Key: "a", Value: "1"}}); err != nil {
+		t.Error(err)
+	}
+
+	expect := File{"system": {"root": "/"}, "app": {"theme": "light"}, "new": {"a": "1"}}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %v, got %v", expect, f)
+	}
+	if v, _ := p.Get("system", "root"); v != "/" || !p.ReadOnly("system") || p.ReadOnly("app") {
+		t.Error("unexpected read access")
+	}
+}
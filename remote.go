@@ -0,0 +1,118 @@
+package ini
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// A Remote keeps a File from a Source, typically a URLSource, up to date. The
+// File is loaded again when it is requested after it expired, TTL plus a random
+// part of Jitter after it was last loaded. The Jitter keeps many instances of a
+// service from all asking the config server at once. A Remote is safe for
+// concurrent use by multiple goroutines. Only one load runs at a time and it
+// does not block other callers, they get the last File meanwhile.
+//
+// Files returned from a Remote are shared between callers and must not be
+// modified.
+type Remote struct {
+	Source  Source
+	TTL     time.Duration
+	Jitter  time.Duration
+	Options []Option // passed to LoadSource
+
+	mu      sync.Mutex
+	file    File
+	err     error // of the last load
+	expires time.Time
+	loading *remoteLoad      // the running load or nil
+	now     func() time.Time // time.Now, replaced in tests
+}
+
+// remoteLoad is a load of a Remote's Source, done is closed when it finished.
+type remoteLoad struct {
+	done chan struct{}
+	err  error
+}
+
+// NewRemote returns a Remote for source with the given TTL and a Jitter of a
+// tenth of the TTL. Nothing is loaded until the first call to File or Refresh.
+func NewRemote(source Source, ttl time.Duration, opts ...Option) *Remote {
+	return &Remote{Source: source, TTL: ttl, Jitter: ttl / 10, Options: opts}
+}
+
+// File returns the current File, loading it if it expired. If loading fails,
+// it returns the last File that was loaded successfully, if any, along with
+// the error, so a service can keep running with its last known configuration.
+// The next attempt is made after the TTL, like after a successful load. Until
+// then File returns the last File without an error, or the error if nothing
+// was ever loaded.
+//
+// The caller that finds the File expired waits for the load. Callers that come
+// while it runs get the last File right away, or wait as well if there is none
+// yet.
+func (r *Remote) File() (File, error) {
+	r.mu.Lock()
+	if !r.expires.IsZero() && r.clock().Before(r.expires) {
+		defer r.mu.Unlock()
+		if r.file == nil {
+			return nil, r.err
+		}
+		return r.file, nil
+	}
+	l, started := r.startLoad()
+	stale := r.file
+	r.mu.Unlock()
+	if !started && stale != nil {
+		return stale, nil
+	}
+	<-l.done
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file, l.err
+}
+
+// Refresh loads the File now, regardless of whether it expired, e.g. after
+// being notified of a change. On failure, File keeps returning the last File
+// that was loaded successfully. If a load is already running, Refresh waits
+// for it instead of starting another one.
+func (r *Remote) Refresh() error {
+	r.mu.Lock()
+	l, _ := r.startLoad()
+	r.mu.Unlock()
+	<-l.done
+	return l.err
+}
+
+// startLoad returns the running load, or starts a new one. r.mu must be held.
+func (r *Remote) startLoad() (l *remoteLoad, started bool) {
+	if r.loading != nil {
+		return r.loading, false
+	}
+	l = &remoteLoad{done: make(chan struct{})}
+	r.loading = l
+	go func() {
+		f, err := LoadSource(r.Source, r.Options...)
+		r.mu.Lock()
+		delay := r.TTL
+		if r.Jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(r.Jitter)))
+		}
+		r.expires = r.clock().Add(delay)
+		if err == nil {
+			r.file = f
+		}
+		r.err, l.err = err, err
+		r.loading = nil
+		r.mu.Unlock()
+		close(l.done)
+	}()
+	return l, true
+}
+
+func (r *Remote) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
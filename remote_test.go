@@ -0,0 +1,120 @@
+package ini
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// countingSource returns the INI text "n = <calls to Open>", or fails if err
+// is set.
+type countingSource struct {
+	calls int
+	err   error
+}
+
+func (s *countingSource) Name() string { return "counting" }
+
+func (s *countingSource) Open() (io.ReadCloser, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return BytesSource("", []byte("n = "+strconv.Itoa(s.calls))).Open()
+}
+
+func TestRemote(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &countingSource{}
+	r := NewRemote(source, time.Minute)
+	r.now = func() time.Time { return now }
+
+	check := func(expect string, expectErr bool) {
+		t.Helper()
+		f, err := r.File()
+		if (err != nil) != expectErr {
+			t.Errorf("unexpected error %v", err)
+		}
+		if got := f[""]["n"]; got != expect {
+			t.Errorf("expected n = %s, got %q", expect, got)
+		}
+	}
+
+	check("1", false)
+	now = now.Add(59 * time.Second)
+	check("1", false)
+	now = now.Add(time.Minute + r.Jitter)
+	check("2", false)
+
+	if err := r.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	check("3", false)
+
+	source.err = errors.New("server down")
+	now = now.Add(time.Minute + r.Jitter)
+	check("3", true)
+	check("3", false)
+	if source.calls != 4 {
+		t.Errorf("expected no retry before the TTL, got %d calls", source.calls)
+	}
+	if err := r.Refresh(); err == nil {
+		t.Error("expected Refresh to report the error")
+	}
+}
+
+// blockingSource blocks in Open until release is closed.
+type blockingSource struct {
+	opened  chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSource) Name() string { return "blocking" }
+
+func (s *blockingSource) Open() (io.ReadCloser, error) {
+	s.opened <- struct{}{}
+	<-s.release
+	return BytesSource("", []byte("n = new")).Open()
+}
+
+func TestRemoteServesStaleWhileLoading(t *testing.T) {
+	r := NewRemote(BytesSource("", []byte("n = old")), time.Minute)
+	if _, err := r.File(); err != nil {
+		t.Fatal(err)
+	}
+	source := &blockingSource{make(chan struct{}), make(chan struct{})}
+	r.Source = source
+	r.mu.Lock()
+	r.expires = time.Now().Add(-time.Second)
+	r.mu.Unlock()
+
+	loaded := make(chan File)
+	go func() {
+		f, _ := r.File()
+		loaded <- f
+	}()
+	<-source.opened
+	f, err := r.File()
+	if err != nil || f[""]["n"] != "old" {
+		t.Errorf("expected the stale File during the load, got %v, %v", f, err)
+	}
+	close(source.release)
+	if f := <-loaded; f[""]["n"] != "new" {
+		t.Errorf("expected the new File, got %v", f)
+	}
+}
+
+func TestRemoteFirstLoadFails(t *testing.T) {
+	source := &countingSource{err: errors.New("server down")}
+	r := NewRemote(source, time.Minute)
+	for i := 0; i < 3; i++ {
+		if f, err := r.File(); f != nil || err == nil {
+			t.Errorf("expected error, got %v, %v", f, err)
+		}
+	}
+	if source.calls != 1 {
+		t.Errorf("expected no retry before the TTL, got %d calls", source.calls)
+	}
+}
@@ -0,0 +1,73 @@
+package ini
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// RoundTrip reads src, writes the File and reads the output again, all with
+// the given options, and reports whether both Files are the same. If not,
+// diff describes every difference on a line of its own. err is an error from
+// reading src or writing the File, or from reading the written output, which
+// also means that the options do not round-trip.
+//
+// RoundTrip is meant for fuzz tests of option combinations, e.g.
+//
+//	f.Fuzz(func(t *testing.T, src []byte) {
+//		same, diff, err := ini.RoundTrip(src, ini.QuotedValues())
+//		if err == nil && !same {
+//			t.Errorf("round trip changed the file:\n%s", diff)
+//		}
+//	})
+func RoundTrip(src []byte, opts ...Option) (same bool, diff string, err error) {
+	o := makeOptions(opts)
+	first, err := read(bytes.NewReader(src), o)
+	if err != nil {
+		return false, "", err
+	}
+	var buf bytes.Buffer
+	if err := first.write(&buf, o); err != nil {
+		return false, "", err
+	}
+	second, err := read(bytes.NewReader(buf.Bytes()), makeOptions(opts))
+	if err != nil {
+		return false, "", fmt.Errorf("ini: reading written output: %w", err)
+	}
+	diff = diffFiles(first, second)
+	return diff == "", diff, nil
+}
+
+// diffFiles describes the differences between two Files, empty sections in
+// a are ignored if b does not have them.
+func diffFiles(a, b File) string {
+	var lines []string
+	names := sortedKeys(a)
+	for _, name := range sortedKeys(b) {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		sa, sb := a[name], b[name]
+		keys := sortedSectionKeys(sa)
+		for _, key := range sortedSectionKeys(sb) {
+			if _, ok := sa[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		for _, key := range keys {
+			va, inA := sa[key]
+			vb, inB := sb[key]
+			switch {
+			case !inB:
+				lines = append(lines, fmt.Sprintf("[%s] %s: %q is lost", name, key, va))
+			case !inA:
+				lines = append(lines, fmt.Sprintf("[%s] %s: %q is added", name, key, vb))
+			case va != vb:
+				lines = append(lines, fmt.Sprintf("[%s] %s: %q becomes %q", name, key, va, vb))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
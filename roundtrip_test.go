@@ -0,0 +1,45 @@
+package ini
+
+import "testing"
+
+func TestRoundTrip(t *testing.T) {
+	same, diff, err := RoundTrip([]byte("[s]\na = 1\nb = x y"))
+	if err != nil || !same || diff != "" {
+		t.Errorf("expected same, got %v, %q, %v", same, diff, err)
+	}
+
+	src := []byte("a = '\"quoted\"'\nb = plain")
+	if same, _, err := RoundTrip(src, QuotedValues()); err != nil || !same {
+		t.Errorf("QuotedValues must round-trip, got %v, %v", same, err)
+	}
+
+	// Escapes are written but not decoded without UnicodeEscapes.
+	same, diff, err = RoundTrip([]byte("a = caf\u00e9"), EscapeNonASCII())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "[] a: \"caf\u00e9\" becomes \"caf\\\\u00E9\""; same || diff != expect {
+		t.Errorf("expected difference %s, got %v, %s", expect, same, diff)
+	}
+	if same, _, err := RoundTrip([]byte("a = caf\u00e9"), EscapeNonASCII(), UnicodeEscapes()); err != nil || !same {
+		t.Errorf("escapes must round-trip with UnicodeEscapes, got %v, %v", same, err)
+	}
+
+	if _, _, err := RoundTrip([]byte("invalid")); err == nil {
+		t.Error("expected error for invalid input")
+	}
+}
+
+func TestDiffFiles(t *testing.T) {
+	diff := diffFiles(
+		File{"s": {"a": "1", "b": "2"}, "t": {"x": "1"}},
+		File{"s": {"a": "1", "b": "3", "c": "4"}, "u": {"y": "5"}},
+	)
+	expect := `[s] b: "2" becomes "3"` + "\n" +
+		`[s] c: "4" is added` + "\n" +
+		`[t] x: "1" is lost` + "\n" +
+		`[u] y: "5" is added`
+	if diff != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, diff)
+	}
+}
@@ -0,0 +1,379 @@
+package ini
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// A Schema describes the keys that Files are expected to contain. It maps
+// section names to key names to the Rule for that key.
+type Schema map[string]map[string]Rule
+
+// A Rule describes the allowed values of a single key.
+type Rule struct {
+	Type     string // one of "string" (the default if empty), "int", "float", "bool" or "duration"
+	Required bool
+
+	// If HasRange is set, numeric values must be in the range [Min, Max].
+	HasRange bool
+	Min, Max float64
+
+	// If MaxLength is positive, values can have at most that many characters.
+	MaxLength int
+
+	// If HasCount is set, the value is a comma-separated list which must have
+	// at least MinCount and at most MaxCount non-empty elements.
+	HasCount           bool
+	MinCount, MaxCount int
+
+	// If Pattern is not nil, values must match it. Use ^ and $ to match the
+	// whole value.
+	Pattern *regexp.Regexp
+}
+
+// A Problem is a violation of a Schema reported by Schema.Validate.
+type Problem struct {
+	Section string
+	Key     string
+	Message string
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("[%s] %s: %s", p.Section, p.Key, p.Message)
+}
+
+// LoadSchema reads a Schema from an INI file on disk, see ParseSchema.
+func LoadSchema(path string) (Schema, error) {
+	f, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchema(f)
+}
+
+// ParseSchema creates a Schema from a File which describes every key with a
+// comma-separated list of terms, e.g.
+//
+//	[server]
+//	host = string, required
+//	port = int, required, range(1, 65535)
+//	timeout = duration
+//	name = string, maxlen(64), match(^[a-z][a-z0-9-]*$)
+//	peers = count(1, 5)
+//
+// A term is either a type name (string, int, float, bool or duration),
+// required, range(min, max), maxlen(n), count(min, max) or match(regexp). A
+// range is only allowed for int and float keys.
+func ParseSchema(f File) (Schema, error) {
+	s := make(Schema)
+	for sectionName, section := range f {
+		rules := make(map[string]Rule)
+		s[sectionName] = rules
+		for key, def := range section {
+			rule, err := parseRule(def)
+			if err != nil {
+				return nil, fmt.Errorf("ini: schema [%s] %s: %v", sectionName, key, err)
+			}
+			rules[key] = rule
+		}
+	}
+	return s, nil
+}
+
+func parseRule(def string) (Rule, error) {
+	var rule Rule
+	for _, term := range splitTerms(def) {
+		name, args, err := parseTerm(term)
+		if err != nil {
+			return rule, err
+		}
+		switch name {
+		case "string", "int", "float", "bool", "duration":
+			if args != nil {
+				return rule, fmt.Errorf("type %s takes no arguments", name)
+			}
+			rule.Type = name
+		case "required":
+			if args != nil {
+				return rule, fmt.Errorf("required takes no arguments")
+			}
+			rule.Required = true
+		case "range":
+			if len(args) != 2 {
+				return rule, fmt.Errorf("range needs a minimum and a maximum")
+			}
+			if rule.Min, err = strconv.ParseFloat(args[0], 64); err != nil {
+				return rule, fmt.Errorf("invalid range minimum %q", args[0])
+			}
+			if rule.Max, err = strconv.ParseFloat(args[1], 64); err != nil {
+				return rule, fmt.Errorf("invalid range maximum %q", args[1])
+			}
+			rule.HasRange = true
+		case "maxlen":
+			if len(args) != 1 {
+				return rule, fmt.Errorf("maxlen needs a length")
+			}
+			if rule.MaxLength, err = strconv.Atoi(args[0]); err != nil || rule.MaxLength <= 0 {
+				return rule, fmt.Errorf("invalid maximum length %q", args[0])
+			}
+		case "count":
+			if len(args) != 2 {
+				return rule, fmt.Errorf("count needs a minimum and a maximum")
+			}
+			if rule.MinCount, err = strconv.Atoi(args[0]); err != nil {
+				return rule, fmt.Errorf("invalid count minimum %q", args[0])
+			}
+			if rule.MaxCount, err = strconv.Atoi(args[1]); err != nil {
+				return rule, fmt.Errorf("invalid count maximum %q", args[1])
+			}
+			rule.HasCount = true
+		case "match":
+			// The expression may contain commas, so it is taken from the
+			// term as a whole rather than from the split arguments.
+			expr := strings.TrimSpace(term[strings.IndexByte(term, '(')+1 : len(term)-1])
+			if rule.Pattern, err = regexp.Compile(expr); err != nil {
+				return rule, fmt.Errorf("invalid pattern %q: %v", expr, err)
+			}
+		default:
+			return rule, fmt.Errorf("unknown term %q", term)
+		}
+	}
+	if rule.HasRange && rule.Type != "int" && rule.Type != "float" {
+		return rule, fmt.Errorf("range needs the type int or float")
+	}
+	return rule, nil
+}
+
+// splitTerms splits a rule definition at commas which are not inside
+// parentheses.
+func splitTerms(def string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, r := range def {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, def[start:i])
+				start = i + 1
+			}
+		}
+	}
+	terms = append(terms, def[start:])
+
+	nonEmpty := terms[:0]
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			nonEmpty = append(nonEmpty, term)
+		}
+	}
+	return nonEmpty
+}
+
+// parseTerm splits a term like range(1, 10) into its name and arguments. The
+// arguments are nil if the term has no parentheses.
+func parseTerm(term string) (name string, args []string, err error) {
+	open := strings.IndexByte(term, '(')
+	if open == -1 {
+		return term, nil, nil
+	}
+	if !strings.HasSuffix(term, ")") {
+		return "", nil, fmt.Errorf("missing closing parenthesis in %q", term)
+	}
+	name = strings.TrimSpace(term[:open])
+	args = strings.Split(term[open+1:len(term)-1], ",")
+	for i := range args {
+		args[i] = strings.TrimSpace(args[i])
+	}
+	return name, args, nil
+}
+
+// Validate checks f against the Schema and returns all problems found, sorted
+// by section and key. Keys that the Schema does not describe are ignored.
+func (s Schema) Validate(f File) []Problem {
+	var problems []Problem
+	for sectionName, rules := range s {
+		for key, rule := range rules {
+			value, ok := f.Get(sectionName, key)
+			if !ok {
+				if rule.Required {
+					problems = append(problems, Problem{sectionName, key, "required key is missing"})
+				}
+				continue
+			}
+			if msg := rule.check(value); msg != "" {
+				problems = append(problems, Problem{sectionName, key, msg})
+			}
+		}
+	}
+	sortProblems(problems)
+	return problems
+}
+
+// check returns a description of what is wrong with value, or the empty string
+// if it satisfies the rule.
+func (r Rule) check(value string) string {
+	var number float64
+	switch r.Type {
+	case "int":
+		n, err := parseInt(value, strconv.IntSize)
+		if err != nil {
+			return fmt.Sprintf("%q is not an integer", value)
+		}
+		number = float64(n)
+	case "float":
+		x, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Sprintf("%q is not a number", value)
+		}
+		number = x
+	case "bool":
+		if _, ok := parseBool(value); !ok {
+			return fmt.Sprintf("%q is not a boolean", value)
+		}
+	case "duration":
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Sprintf("%q is not a duration", value)
+		}
+	}
+	if r.HasRange && (r.Type == "int" || r.Type == "float") {
+		if number < r.Min || number > r.Max {
+			return fmt.Sprintf("%s is not in range [%v, %v]", value, r.Min, r.Max)
+		}
+	}
+	if r.MaxLength > 0 {
+		if n := utf8.RuneCountInString(value); n > r.MaxLength {
+			return fmt.Sprintf("value has %d characters, at most %d are allowed", n, r.MaxLength)
+		}
+	}
+	if r.HasCount {
+		n := len(listElements(value))
+		if n < r.MinCount || n > r.MaxCount {
+			return fmt.Sprintf("list has %d elements, expected %d to %d", n, r.MinCount, r.MaxCount)
+		}
+	}
+	if r.Pattern != nil && !r.Pattern.MatchString(value) {
+		return fmt.Sprintf("%q does not match %s", value, r.Pattern)
+	}
+	return ""
+}
+
+// listElements splits a comma-separated list into its non-empty elements.
+func listElements(value string) []string {
+	var elements []string
+	for _, e := range strings.Split(value, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			elements = append(elements, e)
+		}
+	}
+	return elements
+}
+
+func sortProblems(problems []Problem) {
+	sort.Slice(problems, func(i, j int) bool {
+		a, b := problems[i], problems[j]
+		if a.Section != b.Section {
+			return a.Section < b.Section
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.Message < b.Message
+	})
+}
+
+// durationPattern matches the values accepted by time.ParseDuration.
+const durationPattern = `^[-+]?(0|([0-9]*(\.[0-9]*)?(ns|us|µs|μs|ms|s|m|h))+)$`
+
+// JSONSchema returns a JSON Schema document describing the Schema. The
+// document describes an object with a property per section, each an object
+// with a property per key. Values are typed as in the Schema, so an int key
+// is a JSON integer rather than a string. Keys with a count are JSON arrays
+// of strings, the maxlen and match of such keys apply to the whole list and
+// are left out.
+func (s Schema) JSONSchema() []byte {
+	sections := make(map[string]interface{})
+	var requiredSections []string
+	for sectionName, rules := range s {
+		properties := make(map[string]interface{})
+		required := []string{}
+		for key, rule := range rules {
+			properties[key] = rule.jsonSchema()
+			if rule.Required {
+				required = append(required, key)
+			}
+		}
+		sort.Strings(required)
+		sections[sectionName] = map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		}
+		if len(required) > 0 {
+			requiredSections = append(requiredSections, sectionName)
+		}
+	}
+	sort.Strings(requiredSections)
+	if requiredSections == nil {
+		requiredSections = []string{}
+	}
+	doc := map[string]interface{}{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"type":       "object",
+		"properties": sections,
+		"required":   requiredSections,
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		// The document only contains strings, numbers, maps and slices.
+		panic(err)
+	}
+	return data
+}
+
+func (r Rule) jsonSchema() map[string]interface{} {
+	if r.HasCount {
+		return map[string]interface{}{
+			"type":     "array",
+			"items":    map[string]interface{}{"type": "string"},
+			"minItems": r.MinCount,
+			"maxItems": r.MaxCount,
+		}
+	}
+	schema := make(map[string]interface{})
+	switch r.Type {
+	case "int":
+		schema["type"] = "integer"
+	case "float":
+		schema["type"] = "number"
+	case "bool":
+		schema["type"] = "boolean"
+	case "duration":
+		schema["type"] = "string"
+		schema["pattern"] = durationPattern
+	default:
+		schema["type"] = "string"
+	}
+	if r.HasRange && (r.Type == "int" || r.Type == "float") {
+		schema["minimum"] = r.Min
+		schema["maximum"] = r.Max
+	}
+	if schema["type"] == "string" {
+		if r.MaxLength > 0 {
+			schema["maxLength"] = r.MaxLength
+		}
+		if r.Pattern != nil && r.Type != "duration" {
+			schema["pattern"] = r.Pattern.String()
+		}
+	}
+	return schema
+}
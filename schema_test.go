@@ -0,0 +1,157 @@
+package ini
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSchema(t *testing.T) {
+	s, err := ParseSchema(MustParse(`
+[server]
+host = string, required
+port = int, required, range(1, 65535)
+timeout = duration
+debug = bool`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := Schema{"server": {
+		"host":    {Type: "string", Required: true},
+		"port":    {Type: "int", Required: true, HasRange: true, Min: 1, Max: 65535},
+		"timeout": {Type: "duration"},
+		"debug":   {Type: "bool"},
+	}}
+	if !reflect.DeepEqual(s, expect) {
+		t.Errorf("expected %v, got %v", expect, s)
+	}
+
+	for _, def := range []string{"integer", "range(1)", "range(a, b)", "required(x)", "range(1, 2", "range(1, 2)", "bool, range(1, 2)"} {
+		if _, err := ParseSchema(File{"s": {"k": def}}); err == nil {
+			t.Errorf("%q: expected error", def)
+		}
+	}
+	if _, err := ParseSchema(File{"s": {"k": "range(0, 1), float"}}); err != nil {
+		t.Errorf("the type may follow the range: %v", err)
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	s := Schema{"server": {
+		"host":    {Required: true},
+		"port":    {Type: "int", Required: true, HasRange: true, Min: 1, Max: 65535},
+		"timeout": {Type: "duration"},
+		"debug":   {Type: "bool"},
+	}}
+	if problems := s.Validate(MustParse("[server]\nhost = x\nport = 80\ndebug = on\nextra = 1")); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+
+	problems := s.Validate(MustParse("[server]\nport = 70000\ntimeout = soon\ndebug = maybe"))
+	expect := []Problem{
+		{"server", "debug", `"maybe" is not a boolean`},
+		{"server", "host", "required key is missing"},
+		{"server", "port", "70000 is not in range [1, 65535]"},
+		{"server", "timeout", `"soon" is not a duration`},
+	}
+	if !reflect.DeepEqual(problems, expect) {
+		t.Errorf("expected %v, got %v", expect, problems)
+	}
+}
+
+func TestSchemaLimits(t *testing.T) {
+	s, err := ParseSchema(MustParse(`
+[app]
+name = string, maxlen(8), match(^[a-z]{2,}$)
+peers = count(1, 3)
+id = match(^(a|b),(c|d)$)`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, def := range []string{"maxlen(0)", "maxlen(x)", "count(1)", "count(a, 2)", "match([)"} {
+		if _, err := ParseSchema(File{"s": {"k": def}}); err == nil {
+			t.Errorf("%q: expected error", def)
+		}
+	}
+
+	if problems := s.Validate(MustParse("[app]\nname = abc\npeers = a, b\nid = a,d")); len(problems) != 0 {
+		t.Errorf("expected no problems, got %v", problems)
+	}
+
+	problems := s.Validate(MustParse("[app]\nname = waytoolong\npeers = a, , b, c, d\nid = a,b"))
+	expect := []Problem{
+		{"app", "id", `"a,b" does not match ^(a|b),(c|d)$`},
+		{"app", "name", "value has 10 characters, at most 8 are allowed"},
+		{"app", "peers", "list has 4 elements, expected 1 to 3"},
+	}
+	if !reflect.DeepEqual(problems, expect) {
+		t.Errorf("expected %v, got %v", expect, problems)
+	}
+
+	problems = s.Validate(MustParse("[app]\nname = A1\npeers ="))
+	expect = []Problem{
+		{"app", "name", `"A1" does not match ^[a-z]{2,}$`},
+		{"app", "peers", "list has 0 elements, expected 1 to 3"},
+	}
+	if !reflect.DeepEqual(problems, expect) {
+		t.Errorf("expected %v, got %v", expect, problems)
+	}
+}
+
+func TestSchemaJSONSchema(t *testing.T) {
+	s := Schema{
+		"server": {
+			"port":    {Type: "int", Required: true, HasRange: true, Min: 1, Max: 65535},
+			"timeout": {Type: "duration"},
+		},
+		"ui": {
+			"dark":  {Type: "bool"},
+			"peers": {HasCount: true, MinCount: 1, MaxCount: 3},
+		},
+	}
+	expect := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "server": {
+      "properties": {
+        "port": {
+          "maximum": 65535,
+          "minimum": 1,
+          "type": "integer"
+        },
+        "timeout": {
+          "pattern": "^[-+]?(0|([0-9]*(\\.[0-9]*)?(ns|us|µs|μs|ms|s|m|h))+)$",
+          "type": "string"
+        }
+      },
+      "required": [
+        "port"
+      ],
+      "type": "object"
+    },
+    "ui": {
+      "properties": {
+        "dark": {
+          "type": "boolean"
+        },
+        "peers": {
+          "items": {
+            "type": "string"
+          },
+          "maxItems": 3,
+          "minItems": 1,
+          "type": "array"
+        }
+      },
+      "required": [],
+      "type": "object"
+    }
+  },
+  "required": [
+    "server"
+  ],
+  "type": "object"
+}`
+	if got := string(s.JSONSchema()); got != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, got)
+	}
+}
@@ -0,0 +1,131 @@
+package ini
+
+import (
+	"strconv"
+	"strings"
+)
+
+// BoolStyle is the pair of words that SetBool writes, see BoolFormat.
+type BoolStyle int
+
+const (
+	// BoolTrueFalse writes true and false. This is the default.
+	BoolTrueFalse BoolStyle = iota
+	// BoolYesNo writes yes and no.
+	BoolYesNo
+	// BoolOnOff writes on and off.
+	BoolOnOff
+	// BoolOneZero writes 1 and 0.
+	BoolOneZero
+)
+
+// BoolFormat makes SetBool write booleans in the given style, to match what
+// the program reading the file expects. GetBool reads all of them.
+func BoolFormat(style BoolStyle) Option {
+	return func(o *options) {
+		o.boolStyle = style
+	}
+}
+
+// SetBool stores a boolean for a key in a section, as true or false unless
+// the BoolFormat option says otherwise.
+func (f File) SetBool(section, key string, b bool, opts ...Option) {
+	words := [...][2]string{
+		BoolTrueFalse: {"false", "true"},
+		BoolYesNo:     {"no", "yes"},
+		BoolOnOff:     {"off", "on"},
+		BoolOneZero:   {"0", "1"},
+	}
+	style := makeOptions(opts).boolStyle
+	if style < 0 || int(style) >= len(words) {
+		style = BoolTrueFalse
+	}
+	value := words[style][0]
+	if b {
+		value = words[style][1]
+	}
+	f.Set(section, key, value, opts...)
+}
+
+// IntBase makes SetInt, SetInt64 and SetUint64 write integers in base 2, 8, 10
+// or 16. Other than decimal numbers, they get the prefix 0b, 0o or 0x, which
+// GetInt understands, e.g. 0xff for a mask.
+func IntBase(base int) Option {
+	return func(o *options) {
+		o.intBase = base
+	}
+}
+
+// FloatFormat makes SetFloat write numbers with the given format and
+// precision, see strconv.FormatFloat. The default is 'g' with the smallest
+// precision that reads back as the same number.
+func FloatFormat(format byte, precision int) Option {
+	return func(o *options) {
+		o.floatFormat, o.floatPrecision = format, precision
+	}
+}
+
+var basePrefixes = map[int]string{2: "0b", 8: "0o", 16: "0x"}
+
+// SetInt stores an integer for a key in a section, in decimal unless the
+// IntBase option says otherwise. GetInt reads it back.
+func (f File) SetInt(section, key string, n int, opts ...Option) {
+	f.SetInt64(section, key, int64(n), opts...)
+}
+
+// SetInt64 is like SetInt but takes an int64, GetInt64 reads it back.
+func (f File) SetInt64(section, key string, n int64, opts ...Option) {
+	o := makeOptions(opts)
+	prefix, ok := basePrefixes[o.intBase]
+	if !ok {
+		f.Set(section, key, strconv.FormatInt(n, 10), opts...)
+		return
+	}
+	sign, abs := "", uint64(n)
+	if n < 0 {
+		sign, abs = "-", uint64(-n)
+	}
+	f.Set(section, key, sign+prefix+strconv.FormatUint(abs, o.intBase), opts...)
+}
+
+// SetUint64 stores an unsigned integer for a key in a section, see SetInt.
+// GetUint64 reads it back.
+func (f File) SetUint64(section, key string, n uint64, opts ...Option) {
+	o := makeOptions(opts)
+	prefix, ok := basePrefixes[o.intBase]
+	if !ok {
+		prefix, o.intBase = "", 10
+	}
+	f.Set(section, key, prefix+strconv.FormatUint(n, o.intBase), opts...)
+}
+
+// SetFloat stores a floating-point number for a key in a section, see
+// FloatFormat.
+func (f File) SetFloat(section, key string, x float64, opts ...Option) {
+	o := makeOptions(opts)
+	format, precision := o.floatFormat, o.floatPrecision
+	if format == 0 {
+		format, precision = 'g', -1
+	}
+	f.Set(section, key, strconv.FormatFloat(x, format, precision, 64), opts...)
+}
+
+// SetStrings stores a list of values for a key in a section, one element per
+// line. Writing puts every element on its own indented continuation line:
+//
+//	allow = 10.0.0.1
+//	    10.0.0.2
+//	    10.0.0.3
+//
+// Write and read the file with Continuations(IndentContinuation) and use
+// GetStrings to get the list back. Elements are trimmed and empty elements are
+// left out since they can not be told apart from the end of the value.
+func (f File) SetStrings(section, key string, values []string, opts ...Option) {
+	lines := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			lines = append(lines, v)
+		}
+	}
+	f.Set(section, key, strings.Join(lines, "\n"), opts...)
+}
@@ -0,0 +1,130 @@
+package ini
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSetBool(t *testing.T) {
+	f := make(File)
+	for style, expect := range map[BoolStyle][2]string{
+		BoolTrueFalse: {"true", "false"},
+		BoolYesNo:     {"yes", "no"},
+		BoolOnOff:     {"on", "off"},
+		BoolOneZero:   {"1", "0"},
+	} {
+		f.SetBool("", "t", true, BoolFormat(style))
+		f.SetBool("", "f", false, BoolFormat(style))
+		if f[""]["t"] != expect[0] || f[""]["f"] != expect[1] {
+			t.Errorf("style %d: expected %q, got %q, %q", style, expect, f[""]["t"], f[""]["f"])
+		}
+		if b, err := f.GetBool("", "t"); err != nil || !b {
+			t.Errorf("style %d: GetBool must read %q back as true", style, f[""]["t"])
+		}
+		if b, err := f.GetBool("", "f"); err != nil || b {
+			t.Errorf("style %d: GetBool must read %q back as false", style, f[""]["f"])
+		}
+	}
+	f.SetBool("", "d", true)
+	if f[""]["d"] != "true" {
+		t.Errorf("expected default true, got %q", f[""]["d"])
+	}
+}
+
+func TestNumberFormats(t *testing.T) {
+	f := make(File)
+	f.SetInt("", "mask", 255, IntBase(16))
+	f.SetInt("", "neg", -8, IntBase(8))
+	f.SetInt("", "bits", 5, IntBase(2))
+	f.SetInt("", "dec", -42)
+	f.SetUint64("", "big", 1<<64-1, IntBase(16))
+	f.SetFloat("", "pi", 3.14159, FloatFormat('f', 2))
+	f.SetFloat("", "third", 1.0/3)
+	f.SetFloat("", "exp", 1.5e10, FloatFormat('e', 3))
+	expect := Section{
+		"mask": "0xff", "neg": "-0o10", "bits": "0b101", "dec": "-42",
+		"big": "0xffffffffffffffff", "pi": "3.14", "third": "0.3333333333333333", "exp": "1.500e+10",
+	}
+	for key, value := range expect {
+		if f[""][key] != value {
+			t.Errorf("%s: expected %q, got %q", key, value, f[""][key])
+		}
+	}
+
+	f.SetInt64("", "min", math.MinInt64, IntBase(16))
+	if got, err := f.GetInt64("", "min"); err != nil || got != math.MinInt64 {
+		t.Errorf("min: expected %d, got %d, %v", int64(math.MinInt64), got, err)
+	}
+	for key, n := range map[string]int{"mask": 255, "neg": -8, "bits": 5, "dec": -42} {
+		if got, err := f.GetInt("", key); err != nil || got != n {
+			t.Errorf("%s: expected %d, got %d, %v", key, n, got, err)
+		}
+	}
+	if got, err := f.GetUint64("", "big"); err != nil || got != 1<<64-1 {
+		t.Errorf("big: got %d, %v", got, err)
+	}
+	f = MustParse("upper = 0XFF\nlead = 010\nbad = 0x\nover = 0x100")
+	if n, _ := f.GetInt("", "upper"); n != 255 {
+		t.Errorf("expected 0XFF to be 255, got %d", n)
+	}
+	if n, _ := f.GetInt("", "lead"); n != 10 {
+		t.Errorf("a leading zero must stay decimal, got %d", n)
+	}
+	if _, err := f.GetInt("", "bad"); err == nil {
+		t.Error("expected error for a prefix without digits")
+	}
+	if _, err := f.GetUint8("", "over"); err == nil {
+		t.Error("expected range error for 0x100 as uint8")
+	}
+	f = MustParse("minus = 0x-5\nplus = 0x+5\nuplus = 0b+1")
+	for _, key := range []string{"minus", "plus"} {
+		if n, err := f.GetInt("", key); err == nil {
+			t.Errorf("%s: expected error for a sign after the prefix, got %d", key, n)
+		}
+		if v, _ := f.GetAny("", key); v != f[""][key] {
+			t.Errorf("%s: expected GetAny to return the string, got %v", key, v)
+		}
+	}
+	if n, err := f.GetUint64("", "uplus"); err == nil {
+		t.Errorf("expected error for a sign after the prefix, got %d", n)
+	}
+}
+
+func TestSetStrings(t *testing.T) {
+	f := File{}
+	f.SetStrings("net", "allow", []string{"10.0.0.1", " 10.0.0.2 ", "", "10.0.0.3"})
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err == nil {
+		t.Error("expected error for line breaks without IndentContinuation")
+	}
+	buf.Reset()
+	if err := f.Write(&buf, Continuations(IndentContinuation)); err != nil {
+		t.Fatal(err)
+	}
+	expect := "[net]\nallow = 10.0.0.1\n    10.0.0.2\n    10.0.0.3\n"
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\nbut got\n%s", expect, buf.String())
+	}
+
+	read, err := Read(&buf, Continuations(IndentContinuation))
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := read.GetStrings("net", "allow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(list, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}) {
+		t.Errorf("unexpected list %q", list)
+	}
+	if _, err := read.GetStrings("net", "missing"); err == nil {
+		t.Error("expected error for missing key")
+	}
+
+	src := []byte("[s]\nlist = a\n  b\n  c\nother = 1")
+	if same, diff, err := RoundTrip(src, Continuations(IndentContinuation)); err != nil || !same {
+		t.Errorf("multi-line values must round-trip, got %v, %s, %v", same, diff, err)
+	}
+}
@@ -0,0 +1,154 @@
+package ini
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// A SafeFile is a File from disk that is safe for concurrent use by multiple
+// goroutines. Reload reads it again, Watch does so automatically when the file
+// changes.
+type SafeFile struct {
+	path string
+	opts []Option
+
+	mu      sync.RWMutex
+	file    File
+	modTime time.Time
+	size    int64
+}
+
+// LoadSafe loads the file at path into a new SafeFile.
+func LoadSafe(path string, opts ...Option) (*SafeFile, error) {
+	s := &SafeFile{path: path, opts: opts}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+var shared = struct {
+	sync.Mutex
+	files map[string]*SafeFile
+}{files: make(map[string]*SafeFile)}
+
+// Shared returns the process-wide SafeFile for path, so that packages of a
+// program can share one configuration without passing it around. The file is
+// loaded by the first call for a path, with the options of that call, later
+// calls return the same SafeFile and ignore their options. Paths are compared
+// after making them absolute. If loading fails, the next call tries again.
+func Shared(path string, opts ...Option) (*SafeFile, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	shared.Lock()
+	defer shared.Unlock()
+	if s, ok := shared.files[abs]; ok {
+		return s, nil
+	}
+	s, err := LoadSafe(abs, opts...)
+	if err != nil {
+		return nil, err
+	}
+	shared.files[abs] = s
+	return s, nil
+}
+
+// Path returns the path of the file on disk.
+func (s *SafeFile) Path() string {
+	return s.path
+}
+
+// Get looks up a value like File.Get.
+func (s *SafeFile) Get(section, key string) (value string, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.file.Get(section, key)
+}
+
+// Set stores a value like File.Set. The change is lost on Reload unless it is
+// saved first.
+func (s *SafeFile) Set(section, key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.file.Set(section, key, value, s.opts...)
+}
+
+// File returns a copy of the current contents.
+func (s *SafeFile) File() File {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f := make(File)
+	f.Merge(s.file)
+	return f
+}
+
+// Reload reads the file from disk again. If that fails, the contents stay as
+// they were.
+func (s *SafeFile) Reload() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return err
+	}
+	f, err := Load(s.path, s.opts...)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.file, s.modTime, s.size = f, info.ModTime(), info.Size()
+	return nil
+}
+
+// Save writes the current contents to the file on disk.
+func (s *SafeFile) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.file.Save(s.path, s.opts...); err != nil {
+		return err
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		s.modTime, s.size = info.ModTime(), info.Size()
+	}
+	return nil
+}
+
+// Watch checks the file every interval and reloads it when its modification
+// time or size changed. If onReload is not nil, it is called with the result
+// of every such reload. Call stop to end watching.
+func (s *SafeFile) Watch(interval time.Duration, onReload func(error)) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if !s.changed() {
+					continue
+				}
+				err := s.Reload()
+				if onReload != nil {
+					onReload(err)
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+func (s *SafeFile) changed() bool {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !info.ModTime().Equal(s.modTime) || info.Size() != s.size
+}
@@ -0,0 +1,72 @@
+package ini
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestShared(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.ini")
+	writeFiles(t, dir, map[string]string{"app.ini": "a = 1"})
+
+	s, err := Shared(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := Shared(filepath.Join(dir, ".", "app.ini"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != again {
+		t.Error("expected the same SafeFile for the same path")
+	}
+	if _, err := Shared(filepath.Join(dir, "missing.ini")); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	s.Set("", "b", "2")
+	if v, _ := again.Get("", "b"); v != "2" {
+		t.Errorf("expected shared change, got %q", v)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.File(), File{"": {"a": "1", "b": "2"}}) {
+		t.Errorf("unexpected contents %v", s.File())
+	}
+}
+
+func TestSafeFileWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.ini")
+	writeFiles(t, dir, map[string]string{"app.ini": "a = 1"})
+	s, err := LoadSafe(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := make(chan error, 1)
+	stop := s.Watch(5*time.Millisecond, func(err error) { reloaded <- err })
+	defer stop()
+
+	if err := ioutil.WriteFile(path, []byte("a = 22"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("file was not reloaded")
+	}
+	if v, _ := s.Get("", "a"); v != "22" {
+		t.Errorf("expected reloaded value, got %q", v)
+	}
+	stop()
+	stop()
+}
@@ -0,0 +1,56 @@
+package ini
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrSignature is returned when reading with the Signed option and the input
+// does not end in a valid signature.
+var ErrSignature = errors.New("ini: missing or invalid HMAC signature")
+
+const signaturePrefix = "; hmac-sha256: "
+
+// Signed makes File.Save and File.Write append a signature comment to the
+// output, an HMAC-SHA256 over the file contents using key, and so does saving
+// a Document read with this option. Read and Load with this option verify the
+// signature and return ErrSignature if it is missing or does not match, for
+// example because the file was edited.
+func Signed(key []byte) Option {
+	return func(o *options) {
+		o.signKey = key
+	}
+}
+
+func signature(key, content []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(content)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// appendSignature appends the signature footer line to content.
+func appendSignature(key, content []byte) []byte {
+	footer := signaturePrefix + signature(key, content) + "\n"
+	return append(content, footer...)
+}
+
+// verifySignature checks the signature footer of data and returns the signed
+// content without the footer.
+func verifySignature(key, data []byte) ([]byte, error) {
+	data = bytes.TrimRight(data, "\r\n")
+	start := bytes.LastIndexByte(data, '\n') + 1
+	content, footer := data[:start], data[start:]
+	footer = bytes.TrimSpace(footer)
+	if !bytes.HasPrefix(footer, []byte(signaturePrefix)) {
+		return nil, ErrSignature
+	}
+	got := footer[len(signaturePrefix):]
+	want := signature(key, content)
+	if !hmac.Equal(got, []byte(want)) {
+		return nil, ErrSignature
+	}
+	return content, nil
+}
@@ -0,0 +1,59 @@
+package ini
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSigned(t *testing.T) {
+	key := []byte("secret key")
+	f := MustParse("[server]\nhost = example.com\nport = 443")
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf, Signed(key)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\n; hmac-sha256: ") {
+		t.Fatalf("expected signature footer, got\n%s", buf.String())
+	}
+
+	back, err := Read(bytes.NewReader(buf.Bytes()), Signed(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, f) {
+		t.Errorf("expected %v, got %v", f, back)
+	}
+
+	// The footer is a comment, so reading without verification still works.
+	if _, err := Read(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Error(err)
+	}
+
+	tampered := strings.Replace(buf.String(), "443", "80", 1)
+	if _, err := Read(strings.NewReader(tampered), Signed(key)); err != ErrSignature {
+		t.Errorf("expected ErrSignature for tampered file, got %v", err)
+	}
+	if _, err := Read(bytes.NewReader(buf.Bytes()), Signed([]byte("other"))); err != ErrSignature {
+		t.Errorf("expected ErrSignature for wrong key, got %v", err)
+	}
+	if _, err := Read(strings.NewReader("a = b"), Signed(key)); err != ErrSignature {
+		t.Errorf("expected ErrSignature for unsigned file, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "signed.ini")
+	if err := f.Save(path, Signed(key)); err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := ioutil.ReadFile(path)
+	if !bytes.Equal(saved, buf.Bytes()) {
+		t.Error("expected Save and Write to produce the same signed output")
+	}
+	if _, err := Load(path, Signed(key)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,53 @@
+package ini
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// ReloadOnSignal reloads the file every time the process receives one of the
+// given signals. If onReload is not nil, it is called with the result of every
+// reload. Call stop to end listening for the signals.
+func (s *SafeFile) ReloadOnSignal(onReload func(error), sig ...os.Signal) (stop func()) {
+	c := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	if len(sig) > 0 {
+		signal.Notify(c, sig...)
+	}
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-c:
+				err := s.Reload()
+				if onReload != nil {
+					onReload(err)
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(c)
+			close(done)
+		})
+	}
+}
+
+// ReloadOnHangup reloads the file when the process receives SIGHUP, the
+// conventional way of telling a daemon to re-read its configuration:
+//
+//	stop := config.ReloadOnHangup(func(err error) {
+//		if err != nil {
+//			log.Println("keeping old configuration:", err)
+//		}
+//	})
+//	defer stop()
+//
+// On systems without SIGHUP, like Windows, it does nothing.
+func (s *SafeFile) ReloadOnHangup(onReload func(error)) (stop func()) {
+	return s.ReloadOnSignal(onReload, hangupSignals...)
+}
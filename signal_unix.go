@@ -0,0 +1,10 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package ini
+
+import (
+	"os"
+	"syscall"
+)
+
+var hangupSignals = []os.Signal{syscall.SIGHUP}
@@ -0,0 +1,59 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package ini
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReloadOnHangup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.ini")
+	writeFiles(t, dir, map[string]string{"app.ini": "a = 1"})
+	s, err := LoadSafe(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := make(chan error, 1)
+	stop := s.ReloadOnHangup(func(err error) { reloaded <- err })
+	defer stop()
+
+	if err := ioutil.WriteFile(path, []byte("a = 2"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("file was not reloaded")
+	}
+	if v, _ := s.Get("", "a"); v != "2" {
+		t.Errorf("expected reloaded value, got %q", v)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("[broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	syscall.Kill(syscall.Getpid(), syscall.SIGHUP)
+	select {
+	case err := <-reloaded:
+		if err == nil {
+			t.Error("expected reload error for broken file")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("file was not reloaded")
+	}
+	if v, _ := s.Get("", "a"); v != "2" {
+		t.Errorf("expected old value to stay, got %q", v)
+	}
+}
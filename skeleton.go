@@ -0,0 +1,104 @@
+package ini
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Skeleton returns an example INI file describing the struct v, which may also
+// be a pointer to a struct. It understands the same layout that
+// GenerateStruct produces: fields of v are keys of the default section and
+// nested struct fields are sections.
+//
+// Names are taken from the ini tag, or the field name if there is none. Fields
+// tagged with ini:"-" are skipped. Further tags describe a key:
+//
+//	Port int `ini:"port,required" default:"8080" usage:"port to listen on"`
+//
+// The usage text is written as a comment above the key and required keys are
+// marked as such. The value written for a key is its default tag, or else the
+// field's value in v unless it is the zero value.
+func Skeleton(v interface{}) []byte {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		panic("ini.Skeleton: expected a struct, got " + val.Kind().String())
+	}
+
+	var buf bytes.Buffer
+	var sections []reflect.StructField
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Type().Field(i)
+		if isSectionField(field) {
+			sections = append(sections, field)
+		} else {
+			writeSkeletonKey(&buf, field, val.Field(i))
+		}
+	}
+	for _, field := range sections {
+		name, _, skip := parseIniTag(field)
+		if skip || field.PkgPath != "" {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("[" + name + "]\n")
+		section := val.FieldByIndex(field.Index)
+		for i := 0; i < section.NumField(); i++ {
+			writeSkeletonKey(&buf, section.Type().Field(i), section.Field(i))
+		}
+	}
+	return buf.Bytes()
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// isSectionField reports whether a struct field describes a section. Structs
+// which marshal to text, like time.Time, are values.
+func isSectionField(field reflect.StructField) bool {
+	return field.Type.Kind() == reflect.Struct &&
+		!field.Type.Implements(textMarshalerType)
+}
+
+func writeSkeletonKey(buf *bytes.Buffer, field reflect.StructField, value reflect.Value) {
+	name, required, skip := parseIniTag(field)
+	if skip || field.PkgPath != "" {
+		return
+	}
+	if usage := field.Tag.Get("usage"); usage != "" {
+		for _, line := range strings.Split(usage, "\n") {
+			buf.WriteString("; " + line + "\n")
+		}
+	}
+	if required {
+		buf.WriteString("; required\n")
+	}
+	def, ok := field.Tag.Lookup("default")
+	if !ok && !value.IsZero() {
+		def = fmt.Sprint(value.Interface())
+	}
+	buf.WriteString(strings.TrimRight(name+" = "+def, " ") + "\n")
+}
+
+// parseIniTag returns the key or section name of a struct field and whether it
+// is required or should be skipped.
+func parseIniTag(field reflect.StructField) (name string, required, skip bool) {
+	tag := field.Tag.Get("ini")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "required" {
+			required = true
+		}
+	}
+	return name, required, false
+}
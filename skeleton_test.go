@@ -0,0 +1,36 @@
+package ini
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSkeleton(t *testing.T) {
+	type config struct {
+		Server struct {
+			Port    int           `ini:"port,required" usage:"port to listen on"`
+			Host    string        `ini:"host" default:"localhost"`
+			Timeout time.Duration `ini:"timeout"`
+		} `ini:"server"`
+		Debug  bool   `ini:"debug"`
+		Secret string `ini:"-"`
+		hidden int
+	}
+	var c config
+	c.Server.Timeout = 90 * time.Second
+
+	expect := `debug =
+
+[server]
+; port to listen on
+; required
+port =
+host = localhost
+timeout = 1m30s
+`
+	if got := string(Skeleton(&c)); got != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, got)
+	}
+	// The skeleton must be a valid INI file.
+	MustParse(expect)
+}
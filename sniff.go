@@ -0,0 +1,214 @@
+package ini
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding is a character encoding reported by Sniff.
+type Encoding int
+
+// The encodings that Sniff tells apart.
+const (
+	EncodingUTF8 Encoding = iota
+	EncodingUTF16LE
+	EncodingUTF16BE
+	EncodingLatin1
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case EncodingUTF8:
+		return "UTF-8"
+	case EncodingUTF16LE:
+		return "UTF-16LE"
+	case EncodingUTF16BE:
+		return "UTF-16BE"
+	case EncodingLatin1:
+		return "Latin-1"
+	}
+	return "unknown encoding"
+}
+
+// A SniffReport describes the format that an INI file most likely has, see
+// Sniff. It is a guess from a sample of the file, not a guarantee.
+type SniffReport struct {
+	Encoding Encoding
+	BOM      bool // the file starts with a byte order mark
+
+	// Delimiters and CommentPrefixes are the characters found to separate
+	// keys from values and to start comments, the most used first. They are
+	// empty if there are no such lines.
+	Delimiters      string
+	CommentPrefixes string
+
+	Continuation ContinuationStyle
+}
+
+// Options returns the options for reading a file in the reported format. This
+// package reads only UTF-8 and Latin-1, UTF-16 files must be decoded first.
+func (r SniffReport) Options() []Option {
+	var opts []Option
+	if r.Encoding == EncodingLatin1 {
+		opts = append(opts, Latin1Fallback())
+	}
+	if r.Delimiters != "" {
+		opts = append(opts, Delimiters(r.Delimiters))
+	}
+	if r.CommentPrefixes != "" {
+		opts = append(opts, CommentPrefixes(r.CommentPrefixes))
+	}
+	return append(opts, Continuations(r.Continuation))
+}
+
+// sniffSampleSize is the number of bytes at the start of the input that Sniff
+// looks at.
+const sniffSampleSize = 64 * 1024
+
+// Sniff reads a sample from the start of r and guesses the encoding and INI
+// dialect of the file, so a program can choose the options for reading files
+// of unknown origin before parsing them. Read errors end the sample early.
+//
+// Delimiters and comment characters are counted among the characters that
+// this package knows, = and : and ; and #. Lines ending in a backslash hint at
+// BackslashContinuation, indented lines without a delimiter after a key hint
+// at IndentContinuation.
+func Sniff(r io.Reader) SniffReport {
+	sample := make([]byte, sniffSampleSize)
+	n, _ := io.ReadFull(r, sample)
+	sample = sample[:n]
+
+	var report SniffReport
+	var text string
+	report.Encoding, report.BOM, text = sniffEncoding(sample, n == sniffSampleSize)
+
+	delimiters := make(map[byte]int)
+	comments := make(map[byte]int)
+	backslashes, indented := 0, 0
+	// afterKey is set while the previous line is a key or its continuation,
+	// keyIndent is the indentation of that key.
+	afterKey, keyIndent := false, 0
+	// continued is set if the previous line ends in a backslash.
+	continued := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		wasContinued := continued
+		continued = strings.HasSuffix(trimmed, `\`)
+		switch {
+		case wasContinued:
+			if continued {
+				backslashes++
+			}
+		case trimmed == "":
+			afterKey = false
+		case trimmed[0] == ';' || trimmed[0] == '#':
+			comments[trimmed[0]]++
+		case trimmed[0] == '[' && trimmed[len(trimmed)-1] == ']':
+			afterKey = false
+		default:
+			i := strings.IndexAny(trimmed, "=:")
+			if afterKey && indent > keyIndent && i == -1 {
+				indented++
+				continue
+			}
+			if continued {
+				backslashes++
+			}
+			if i > 0 {
+				delimiters[trimmed[i]]++
+				afterKey, keyIndent = true, indent
+			}
+		}
+	}
+
+	report.Delimiters = byCount(delimiters)
+	report.CommentPrefixes = byCount(comments)
+	if backslashes > 0 && backslashes >= indented {
+		report.Continuation = BackslashContinuation
+	} else if indented > 0 {
+		report.Continuation = IndentContinuation
+	}
+	return report
+}
+
+// sniffEncoding guesses the encoding of sample and returns it decoded. If the
+// sample is truncated, a character cut off at its end does not count.
+func sniffEncoding(sample []byte, truncated bool) (enc Encoding, bom bool, text string) {
+	switch {
+	case bytes.HasPrefix(sample, []byte{0xEF, 0xBB, 0xBF}):
+		return EncodingUTF8, true, string(sample[3:])
+	case bytes.HasPrefix(sample, []byte{0xFF, 0xFE}):
+		return EncodingUTF16LE, true, decodeUTF16(sample[2:], false)
+	case bytes.HasPrefix(sample, []byte{0xFE, 0xFF}):
+		return EncodingUTF16BE, true, decodeUTF16(sample[2:], true)
+	}
+
+	// ASCII characters in UTF-16 have a zero byte, which does not appear in
+	// text otherwise.
+	var evenZeros, oddZeros int
+	for i, b := range sample {
+		if b == 0 {
+			if i%2 == 0 {
+				evenZeros++
+			} else {
+				oddZeros++
+			}
+		}
+	}
+	if half := len(sample) / 4; oddZeros > half && oddZeros > evenZeros {
+		return EncodingUTF16LE, false, decodeUTF16(sample, false)
+	} else if evenZeros > half && evenZeros > oddZeros {
+		return EncodingUTF16BE, false, decodeUTF16(sample, true)
+	}
+
+	valid := sample
+	if truncated {
+		for i := 1; i < utf8.UTFMax && i <= len(valid); i++ {
+			if utf8.RuneStart(valid[len(valid)-i]) {
+				if !utf8.FullRune(valid[len(valid)-i:]) {
+					valid = valid[:len(valid)-i]
+				}
+				break
+			}
+		}
+	}
+	if !utf8.Valid(valid) {
+		return EncodingLatin1, false, latin1ToUTF8(string(sample))
+	}
+	return EncodingUTF8, false, string(sample)
+}
+
+func decodeUTF16(data []byte, bigEndian bool) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		lo, hi := data[2*i], data[2*i+1]
+		if bigEndian {
+			lo, hi = hi, lo
+		}
+		units[i] = uint16(hi)<<8 | uint16(lo)
+	}
+	return string(utf16.Decode(units))
+}
+
+// byCount returns the characters in counts, the one with the largest count
+// first.
+func byCount(counts map[byte]int) string {
+	chars := make([]byte, 0, len(counts))
+	for c := range counts {
+		chars = append(chars, c)
+	}
+	sort.Slice(chars, func(i, j int) bool {
+		a, b := chars[i], chars[j]
+		if counts[a] != counts[b] {
+			return counts[a] > counts[b]
+		}
+		return a < b
+	})
+	return string(chars)
+}
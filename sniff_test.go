@@ -0,0 +1,77 @@
+package ini
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestSniff(t *testing.T) {
+	utf16LE := func(s string, bom bool) []byte {
+		var b []byte
+		if bom {
+			b = append(b, 0xFF, 0xFE)
+		}
+		for _, u := range utf16.Encode([]rune(s)) {
+			b = append(b, byte(u), byte(u>>8))
+		}
+		return b
+	}
+	python := "# comment\n[server]\nhost: example.com\nallow = a\n    b\n    c\n; other\n# more\n"
+	tests := []struct {
+		name   string
+		src    []byte
+		expect SniffReport
+	}{
+		{"empty", nil, SniffReport{}},
+		{"python", []byte(python), SniffReport{
+			Delimiters:      ":=",
+			CommentPrefixes: "#;",
+			Continuation:    IndentContinuation,
+		}},
+		{"git", []byte("\xEF\xBB\xBF[alias]\n  lg = log \\\n    --graph\n  st = status\n"), SniffReport{
+			BOM:          true,
+			Delimiters:   "=",
+			Continuation: BackslashContinuation,
+		}},
+		{"latin1", []byte("; caf\xE9\nname = Gr\xFC\xDFe\n"), SniffReport{
+			Encoding:        EncodingLatin1,
+			Delimiters:      "=",
+			CommentPrefixes: ";",
+		}},
+		{"utf-8", []byte("name = café\n  indented = key\n"), SniffReport{Delimiters: "="}},
+		{"utf-16 bom", utf16LE("[a]\r\nx = 1\r\n", true), SniffReport{
+			Encoding: EncodingUTF16LE, BOM: true, Delimiters: "=",
+		}},
+		{"utf-16", utf16LE("[a]\r\nx : 1\r\n", false), SniffReport{
+			Encoding: EncodingUTF16LE, Delimiters: ":",
+		}},
+	}
+	for _, test := range tests {
+		if got := Sniff(bytes.NewReader(test.src)); got != test.expect {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.expect, got)
+		}
+	}
+
+	report := Sniff(strings.NewReader(python))
+	f, err := Read(strings.NewReader(python), report.Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := f.Get("server", "allow"); v != "a\nb\nc" {
+		t.Errorf("unexpected value %q", v)
+	}
+
+	bom := "\xEF\xBB\xBF[a]\nx = 1\n"
+	f, err = Read(strings.NewReader(bom), Sniff(strings.NewReader(bom)).Options()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := f.Get("a", "x"); v != "1" {
+		t.Errorf("unexpected value %q after BOM", v)
+	}
+	if EncodingUTF16BE.String() != "UTF-16BE" {
+		t.Errorf("unexpected name %q", EncodingUTF16BE)
+	}
+}
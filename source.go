@@ -0,0 +1,200 @@
+package ini
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A Source is a place that an INI file can be loaded from, see LoadSource and
+// LoadLayers. Implement it to load configurations from other backends, e.g. a
+// key-value store.
+type Source interface {
+	// Name describes the source, e.g. a path or URL. It is used as the Name
+	// of a Layer and as the origin of values, see RecordOrigins.
+	Name() string
+	// Open returns the INI contents. It returns an error satisfying
+	// errors.Is(err, fs.ErrNotExist) if the configuration does not exist.
+	Open() (io.ReadCloser, error)
+}
+
+// FileSource returns a Source for a file on disk. File references and
+// includes are relative to the file's directory.
+func FileSource(path string) Source {
+	return fileSource(path)
+}
+
+type fileSource string
+
+func (s fileSource) Name() string                 { return string(s) }
+func (s fileSource) Open() (io.ReadCloser, error) { return os.Open(string(s)) }
+
+// FSSource returns a Source for the named file in fsys, e.g. an embed.FS.
+func FSSource(fsys fs.FS, name string) Source {
+	return fsSource{fsys, name}
+}
+
+type fsSource struct {
+	fsys fs.FS
+	name string
+}
+
+func (s fsSource) Name() string                 { return s.name }
+func (s fsSource) Open() (io.ReadCloser, error) { return s.fsys.Open(s.name) }
+
+// BytesSource returns a Source for INI contents in memory, with the given
+// name.
+func BytesSource(name string, data []byte) Source {
+	return bytesSource{name, data}
+}
+
+type bytesSource struct {
+	name string
+	data []byte
+}
+
+func (s bytesSource) Name() string { return s.name }
+
+func (s bytesSource) Open() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(s.data)), nil
+}
+
+// EnvSource returns a Source for the environment variables that start with
+// prefix. The rest of a variable's name is the key, in lower case, and a
+// double underscore separates a section name from the key, e.g. with the
+// prefix APP_
+//
+//	APP_DEBUG=1          is debug = 1 in the default section
+//	APP_SERVER__PORT=80  is port = 80 in [server]
+//
+// Its name is "env:" followed by the prefix.
+func EnvSource(prefix string) Source {
+	return envSource(prefix)
+}
+
+type envSource string
+
+func (s envSource) Name() string { return "env:" + string(s) }
+
+// Open returns the variables in INI format. LoadSource does not use it but
+// reads the variables directly, so that values may contain line breaks and
+// surrounding white space.
+func (s envSource) Open() (io.ReadCloser, error) {
+	f, err := s.load(&options{})
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := f.write(&buf, &options{}); err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(&buf), nil
+}
+
+// load adds the variables to a new File as if they were read from a file.
+func (s envSource) load(opts *options) (File, error) {
+	prefix := string(s)
+	env := os.Environ()
+	sort.Strings(env)
+	f := make(File)
+	b := newFileBuilder(f, opts)
+	for _, kv := range env {
+		eq := strings.IndexByte(kv, '=')
+		if eq == -1 || !strings.HasPrefix(kv[:eq], prefix) || eq == len(prefix) {
+			continue
+		}
+		name := strings.ToLower(kv[len(prefix):eq])
+		section, key := "", name
+		if i := strings.Index(name, "__"); i != -1 {
+			section, key = name[:i], name[i+2:]
+		}
+		n := Node{Kind: KeyNode, Section: section, Key: key, Value: kv[eq+1:]}
+		if err := b.add(n); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+// URLSource returns a Source that downloads an INI file with an HTTP GET
+// request. A 404 status means that the configuration does not exist. Requests
+// time out after URLTimeout.
+func URLSource(url string) Source {
+	return urlSource(url)
+}
+
+type urlSource string
+
+func (s urlSource) Name() string { return string(s) }
+
+// URLTimeout is the time limit for the requests of a URLSource, including
+// reading the response.
+var URLTimeout = 30 * time.Second
+
+func (s urlSource) Open() (io.ReadCloser, error) {
+	client := &http.Client{Timeout: URLTimeout}
+	resp, err := client.Get(string(s))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, &fs.PathError{Op: "get", Path: string(s), Err: fs.ErrNotExist}
+		}
+		return nil, fmt.Errorf("ini: GET %s: %s", s, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// LoadSource reads an INI File from a Source.
+func LoadSource(s Source, opts ...Option) (File, error) {
+	if env, ok := s.(envSource); ok {
+		o := makeOptions(opts)
+		o.source = env.Name()
+		return env.load(o)
+	}
+	r, err := s.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	o := makeOptions(opts)
+	o.source = s.Name()
+	if path, ok := s.(fileSource); ok {
+		o.baseDir = filepath.Dir(string(path))
+	}
+	return read(r, o)
+}
+
+// LoadLayers loads Layers from the given sources, in order of increasing
+// precedence. Sources that do not exist result in empty layers. If the last
+// source is a FileSource, changes are saved to it.
+func LoadLayers(sources []Source, opts ...Option) (*Layers, error) {
+	l := &Layers{}
+	for _, s := range sources {
+		f, err := LoadSource(s, opts...)
+		if errors.Is(err, fs.ErrNotExist) {
+			f, err = make(File), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		l.Stack = append(l.Stack, Layer{Name: s.Name(), File: f})
+	}
+	if len(sources) > 0 {
+		if path, ok := sources[len(sources)-1].(fileSource); ok {
+			l.SavePath = string(path)
+		}
+	}
+	return l, nil
+}
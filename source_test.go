@@ -0,0 +1,85 @@
+package ini
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSources(t *testing.T) {
+	check := func(s Source, expect File) {
+		t.Helper()
+		f, err := LoadSource(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(f, expect) {
+			t.Errorf("%s: expected %v, got %v", s.Name(), expect, f)
+		}
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.ini": "[s]\nx = file"})
+	check(FileSource(filepath.Join(dir, "a.ini")), File{"s": {"x": "file"}})
+
+	fsys := fstest.MapFS{"conf/app.ini": {Data: []byte("x = fs")}}
+	check(FSSource(fsys, "conf/app.ini"), File{"": {"x": "fs"}})
+
+	check(BytesSource("mem", []byte("x = mem")), File{"": {"x": "mem"}})
+
+	t.Setenv("INITEST_DEBUG", "1")
+	t.Setenv("INITEST_SERVER__PORT", "80")
+	t.Setenv("INITESTING", "no")
+	check(EnvSource("INITEST_"), File{"": {"debug": "1"}, "server": {"port": "80"}})
+	t.Setenv("INITEST_TLS__CERT", "-----BEGIN-----\nabc\n-----END-----\n")
+	t.Setenv("INITEST_PAD", "  x  ")
+	check(EnvSource("INITEST_"), File{
+		"":       {"debug": "1", "pad": "  x  "},
+		"server": {"port": "80"},
+		"tls":    {"cert": "-----BEGIN-----\nabc\n-----END-----\n"},
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/app.ini" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("x = url"))
+	}))
+	defer server.Close()
+	check(URLSource(server.URL+"/app.ini"), File{"": {"x": "url"}})
+	if _, err := LoadSource(URLSource(server.URL + "/missing.ini")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected ErrNotExist for 404, got %v", err)
+	}
+}
+
+func TestLoadLayers(t *testing.T) {
+	dir := t.TempDir()
+	user := filepath.Join(dir, "user.ini")
+	l, err := LoadLayers([]Source{
+		BytesSource("builtin", []byte("a = 1\nb = 1")),
+		FSSource(fstest.MapFS{}, "missing.ini"),
+		FileSource(user),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Stack) != 3 || l.SavePath != user {
+		t.Fatalf("unexpected layers %+v", l)
+	}
+	l.Set("", "b", "2")
+	if err := l.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if f, err := Load(user); err != nil || !reflect.DeepEqual(f, File{"": {"b": "2"}}) {
+		t.Errorf("unexpected saved file %v, %v", f, err)
+	}
+	if name, _ := l.Origin("", "a"); name != "builtin" {
+		t.Errorf("expected origin builtin, got %q", name)
+	}
+}
@@ -0,0 +1,78 @@
+package ini
+
+import "errors"
+
+// ErrTxDone is returned when using a Tx after Commit or Rollback.
+var ErrTxDone = errors.New("ini: transaction has already been committed or rolled back")
+
+// A Tx collects changes to a File which are applied together on Commit or not
+// at all, see File.Begin. A Tx is not safe for concurrent use.
+type Tx struct {
+	file    File
+	changes Patch
+	done    bool
+}
+
+// Begin starts a transaction on f. The File is not changed until Commit.
+func (f File) Begin() *Tx {
+	return &Tx{file: f}
+}
+
+// Set records a change of the value for a key in a section.
+func (tx *Tx) Set(section, key, value string) error {
+	return tx.add(Change{Section: section, Key: key, Value: value})
+}
+
+// Delete records the removal of a key from a section.
+func (tx *Tx) Delete(section, key string) error {
+	return tx.add(Change{Section: section, Key: key, Delete: true})
+}
+
+func (tx *Tx) add(c Change) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.changes = append(tx.changes, c)
+	return nil
+}
+
+// Get looks up a value like File.Get, as it would be after Commit.
+func (tx *Tx) Get(section, key string) (value string, ok bool) {
+	for i := len(tx.changes) - 1; i >= 0; i-- {
+		if c := tx.changes[i]; c.Section == section && c.Key == key {
+			return c.Value, !c.Delete
+		}
+	}
+	return tx.file.Get(section, key)
+}
+
+// Changes returns the changes recorded so far.
+func (tx *Tx) Changes() Patch {
+	return append(Patch(nil), tx.changes...)
+}
+
+// Validate checks the recorded changes against schema, see File.Validate.
+func (tx *Tx) Validate(schema Schema) []Problem {
+	return tx.file.Validate(tx.changes, schema)
+}
+
+// Commit applies all changes to the File. The options are passed to File.Set,
+// e.g. for Audit.
+func (tx *Tx) Commit(opts ...Option) error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	tx.changes.Apply(tx.file, opts...)
+	return nil
+}
+
+// Rollback discards all changes.
+func (tx *Tx) Rollback() error {
+	if tx.done {
+		return ErrTxDone
+	}
+	tx.done = true
+	tx.changes = nil
+	return nil
+}
@@ -0,0 +1,54 @@
+package ini
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTx(t *testing.T) {
+	f := MustParse("[s]\na = 1\nb = 2")
+	original := MustParse("[s]\na = 1\nb = 2")
+
+	tx := f.Begin()
+	tx.Set("s", "a", "10")
+	tx.Delete("s", "b")
+	tx.Set("t", "c", "3")
+	if !reflect.DeepEqual(f, original) {
+		t.Fatalf("file changed before Commit: %v", f)
+	}
+	if v, ok := tx.Get("s", "a"); v != "10" || !ok {
+		t.Errorf("expected pending value, got %q, %v", v, ok)
+	}
+	if _, ok := tx.Get("s", "b"); ok {
+		t.Error("expected pending deletion")
+	}
+	if problems := tx.Validate(Schema{"s": {"a": {Type: "int"}, "b": {Required: true}}}); len(problems) != 1 {
+		t.Errorf("expected a problem for the deleted key, got %v", problems)
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	expect := File{"s": {"a": "10"}, "t": {"c": "3"}}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("expected %v, got %v", expect, f)
+	}
+	if err := tx.Commit(); err != ErrTxDone {
+		t.Errorf("expected ErrTxDone, got %v", err)
+	}
+	if err := tx.Set("s", "a", "x"); err != ErrTxDone {
+		t.Errorf("expected ErrTxDone, got %v", err)
+	}
+
+	tx = f.Begin()
+	tx.Set("s", "a", "lost")
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f, expect) {
+		t.Errorf("file changed by Rollback: %v", f)
+	}
+	if err := tx.Rollback(); err != ErrTxDone {
+		t.Errorf("expected ErrTxDone, got %v", err)
+	}
+}
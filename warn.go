@@ -0,0 +1,30 @@
+package ini
+
+import "fmt"
+
+// A Warning describes something questionable in a file that is not an error,
+// e.g. a key that is set under both its name and an alias, see KeyAlias.
+type Warning struct {
+	Line    int
+	Section string
+	Key     string
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("line %d: [%s] %s: %s", w.Line, w.Section, w.Key, w.Message)
+}
+
+// OnWarning makes reading call fn for every Warning. Without this option,
+// warnings are ignored.
+func OnWarning(fn func(Warning)) Option {
+	return func(o *options) {
+		o.warn = fn
+	}
+}
+
+func (o *options) warning(w Warning) {
+	if o.warn != nil {
+		o.warn(w)
+	}
+}
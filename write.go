@@ -0,0 +1,356 @@
+package ini
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// WriteTo writes the File in INI format to w. Properties of the default
+// section come first, followed by all other sections. Sections and keys are
+// written in sorted order unless the File has an Order, see KeepOrder. Keys
+// and values that would be read back differently, like a key containing "=",
+// make it return an ErrNotWritable.
+func (f File) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := f.write(cw, &options{})
+	return cw.n, err
+}
+
+// Write is like WriteTo but accepts options that change the output, e.g.
+// Signed or EscapeNonASCII.
+func (f File) Write(w io.Writer, opts ...Option) error {
+	return f.write(w, makeOptions(opts))
+}
+
+// seal signs and encrypts data for the Signed and Encrypted options, the
+// reverse of unseal.
+func (o *options) seal(data []byte) ([]byte, error) {
+	if o.signKey != nil {
+		data = appendSignature(o.signKey, data)
+	}
+	if o.cipher != nil {
+		return o.cipher.Encrypt(data)
+	}
+	return data, nil
+}
+
+func (f File) write(w io.Writer, opts *options) error {
+	if opts.cipher != nil || opts.signKey != nil {
+		plain := *opts
+		plain.cipher = nil
+		plain.signKey = nil
+		var buf bytes.Buffer
+		if err := f.write(&buf, &plain); err != nil {
+			return err
+		}
+		data, err := opts.seal(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	}
+
+	opts = opts.withOrderOf(f)
+	if opts.noSections {
+		for _, name := range sortedKeys(f) {
+			if name != "" {
+				return ErrSectionNotAllowed{Section: name}
+			}
+		}
+	}
+
+	bufout := bufio.NewWriter(w)
+	first := true
+	for _, name := range opts.sectionNames(f) {
+		section := f[name]
+		if name == "" && len(section) == 0 {
+			continue
+		}
+		if !first {
+			bufout.WriteString("\n")
+		}
+		first = false
+		if err := writeSection(bufout, name, section, name != "", opts); err != nil {
+			return err
+		}
+	}
+	return bufout.Flush()
+}
+
+// ErrNotWritable is returned for a key or value that can not be written so
+// that reading the output gives the same key and value.
+type ErrNotWritable struct {
+	Section string
+	Key     string
+	Reason  string
+}
+
+func (e ErrNotWritable) Error() string {
+	return fmt.Sprintf("key %q in section [%s] can not be written: %s", e.Key, e.Section, e.Reason)
+}
+
+// checkWritable returns an ErrNotWritable if key or value do not survive
+// writing and reading with the options of o, see checkKey and checkValue.
+func (o *options) checkWritable(section, key, value string) error {
+	reason := ""
+	if !o.anyKeys {
+		reason = o.checkKey(key)
+	}
+	if reason == "" {
+		reason = o.checkValue(value)
+	}
+	if reason != "" {
+		return ErrNotWritable{Section: section, Key: key, Reason: reason}
+	}
+	return nil
+}
+
+// checkKey returns why key would be read back differently, or "" if it would
+// not. The first delimiter of a line ends the key and spaces around it are
+// removed. A line starting with a comment character is a comment and one
+// starting with [ looks like a section header.
+func (o *options) checkKey(key string) string {
+	switch {
+	case key == "":
+		return "the key is empty"
+	case strings.ContainsAny(key, "\r\n"):
+		return "the key contains a line break"
+	case strings.ContainsAny(key, o.delimiterChars()):
+		i := strings.IndexAny(key, o.delimiterChars())
+		return "the key contains the delimiter " + key[i:i+1]
+	case key != strings.TrimSpace(key):
+		return "spaces around the key would be removed"
+	case strings.IndexByte(o.commentChars(), key[0]) != -1:
+		return "a key starting with " + key[:1] + " would be a comment"
+	case key[0] == '[':
+		return "a key starting with [ would look like a section header"
+	}
+	return ""
+}
+
+// checkValue returns why value would be read back differently, or "" if it
+// would not. Spaces around values are removed unless they are quoted, see
+// QuotedValues. Only IndentContinuation reads line breaks back, and only as
+// long as no continued line is blank, which would end the value, or starts
+// with a comment character, which would make it a comment. Spaces around the
+// lines of a multi-line value are removed, they are never quoted. With
+// BackslashContinuation a trailing backslash would continue the value on the
+// next line.
+func (o *options) checkValue(value string) string {
+	if o.continuation == BackslashContinuation && strings.HasSuffix(value, `\`) {
+		return `a trailing \ would continue the value`
+	}
+	if !strings.Contains(value, "\n") {
+		if !o.quotes && value != strings.TrimSpace(value) {
+			return "spaces around the value would be removed, they need QuotedValues"
+		}
+		return ""
+	}
+	if o.continuation != IndentContinuation {
+		return "line breaks need Continuations(IndentContinuation)"
+	}
+	lines := strings.Split(value, "\n")
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			return "a blank line would end the value"
+		}
+		if strings.IndexByte(o.commentChars(), trimmed[0]) != -1 {
+			return "a line starting with " + trimmed[:1] + " would be a comment"
+		}
+	}
+	for _, line := range lines {
+		if line != strings.TrimSpace(line) {
+			return "spaces around the lines of the value would be removed"
+		}
+	}
+	return ""
+}
+
+// keyLine formats a key and its value, which must have passed checkWritable,
+// with the first of the configured delimiters, e.g. "key = value" or
+// "key: value". Line breaks are written as indented continuation lines. Both
+// must already be encoded, see escape and encodeValue.
+func (o *options) keyLine(key, value string) string {
+	if value == "" {
+		return strings.TrimRight(o.keyPrefix(key), " ")
+	}
+	return o.keyPrefix(key) + continueLines(value)
+}
+
+// keyPrefix returns the start of a key line up to its value.
+func (o *options) keyPrefix(key string) string {
+	delimiter := o.delimiterChars()[:1]
+	if delimiter == ":" {
+		return key + ": "
+	}
+	return key + " " + delimiter + " "
+}
+
+// continueLines indents the lines after the first of a multi-line value.
+func continueLines(value string) string {
+	return strings.Replace(value, "\n", "\n    ", -1)
+}
+
+// escape returns a key or section name as written, see EscapeNonASCII.
+func (o *options) escape(s string) string {
+	if o.escapeNonASCII {
+		return encodeNonASCII(s)
+	}
+	return s
+}
+
+// encodeValue returns a value as written, with the escape character of the
+// interpolation style doubled and, see EscapeNonASCII, with \u escapes.
+func (o *options) encodeValue(value string) string {
+	return o.escape(escapeInterpolation(value, o.interpolation))
+}
+
+// writeSection writes the keys of the named section, preceded by its header if
+// header is set.
+func writeSection(w *bufio.Writer, name string, section Section, header bool, opts *options) error {
+	if header {
+		w.WriteString("[" + opts.escape(name) + "]\n")
+	}
+	for _, key := range opts.keys(name, section) {
+		if origin := opts.annotate.Get(name, key); origin != "" {
+			w.WriteString(opts.commentChars()[:1] + " from " + opts.escape(origin) + "\n")
+		}
+		for _, value := range opts.values(name, key, section) {
+			if err := opts.checkWritable(name, key, value); err != nil {
+				return err
+			}
+			if opts.quotes && needsQuotes(value) && !strings.Contains(value, "\n") {
+				value = quoteValue(value, 0)
+			}
+			w.WriteString(opts.keyLine(opts.escape(key), opts.encodeValue(value)) + "\n")
+		}
+	}
+	return nil
+}
+
+// WriteTo writes the keys of the Section in sorted order, in the same format
+// as File.WriteTo. There is no section header since a Section does not know
+// its name, use File.WriteSections for that.
+func (s Section) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := s.Write(cw, "")
+	return cw.n, err
+}
+
+// Write is like WriteTo but accepts options that change the output, like
+// File.Write. KeepOrder and AnnotateOrigins look up the keys of the section by
+// its name in the File, there is still no section header.
+func (s Section) Write(w io.Writer, name string, opts ...Option) error {
+	bufout := bufio.NewWriter(w)
+	if err := writeSection(bufout, name, s, false, makeOptions(opts)); err != nil {
+		return err
+	}
+	return bufout.Flush()
+}
+
+// WriteSections writes only the named sections, in the given order and with
+// their headers, e.g. to print a [database] block that can be pasted into
+// another file. The empty name stands for the default section, which is
+// written without a header. Names of sections that do not exist are ignored.
+func (f File) WriteSections(w io.Writer, names ...string) error {
+	return f.WriteSectionsWith(w, names)
+}
+
+// WriteSectionsWith is like WriteSections but accepts options that change the
+// output like for File.Write, except that Signed and Encrypted do not apply to
+// a part of a file.
+func (f File) WriteSectionsWith(w io.Writer, names []string, opts ...Option) error {
+	o := makeOptions(opts).withOrderOf(f)
+	bufout := bufio.NewWriter(w)
+	first := true
+	for _, name := range names {
+		section, ok := f[name]
+		if !ok {
+			continue
+		}
+		if !first {
+			bufout.WriteString("\n")
+		}
+		first = false
+		if err := writeSection(bufout, name, section, name != "", o); err != nil {
+			return err
+		}
+	}
+	return bufout.Flush()
+}
+
+// AnnotateOrigins makes writing add a comment line before every key that names
+// where its value came from, according to o, e.g.
+//
+//	; from /etc/app/app.ini
+//	port = 80
+//
+// This turns a merged File into a human readable dump of the effective
+// configuration. See RecordOrigins and Layers.Origins for ways to get o. Keys
+// without an origin in o are written without a comment.
+func AnnotateOrigins(o Origins) Option {
+	return func(opts *options) {
+		opts.annotate = o
+	}
+}
+
+// Save writes the File to a file on disk, see WriteTo for the format.
+func (f File) Save(path string, opts ...Option) error {
+	o := makeOptions(opts)
+	var buf bytes.Buffer
+	if err := f.write(&buf, o); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0666); err != nil {
+		return err
+	}
+	o.audit(AuditRecord{Op: "save", Path: path})
+	return nil
+}
+
+// sortedSectionKeys returns the keys of s in sorted order.
+func sortedSectionKeys(s Section) []string {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// Normalize reads an INI file from r and returns it in canonical form, as
+// written by File.Write: sections and keys sorted, whitespace trimmed and all
+// keys written as "key = value", or with the first of the Delimiters. Files
+// with the same meaning normalize to the same bytes, so they can be compared
+// regardless of formatting, comments and order. The options apply to both
+// reading and writing.
+func Normalize(r io.Reader, opts ...Option) ([]byte, error) {
+	o := makeOptions(opts)
+	f, err := read(r, o)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := f.write(&buf, o); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,318 @@
+package ini
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteTo(t *testing.T) {
+	f := File{
+		"":    {"top": "level"},
+		"b":   {"y": "2", "x": "1"},
+		"a":   {},
+		"bar": {"k": "a = b"},
+	}
+	var buf bytes.Buffer
+	n, err := f.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "top = level\n\n[a]\n\n[b]\nx = 1\ny = 2\n\n[bar]\nk = a = b\n"
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, buf.String())
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("expected %d bytes written, got %d", buf.Len(), n)
+	}
+
+	if back := MustParse(buf.String()); !reflect.DeepEqual(back, f) {
+		t.Errorf("round trip: expected %v, got %v", f, back)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	var records []AuditRecord
+	audit := Audit("tester", func(r AuditRecord) {
+		records = append(records, r)
+	})
+	f := MustParse("[s]\nk = old")
+	f.Set("s", "k", "new", audit)
+	f.Set("s", "k2", "v", audit)
+	path := filepath.Join(t.TempDir(), "out.ini")
+	if err := f.Save(path, audit); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	r := records[0]
+	if r.Op != "set" || r.Who != "tester" || r.Section != "s" || r.Key != "k" ||
+		r.Old != "old" || r.New != "new" || !r.Existed || r.Time.IsZero() {
+		t.Errorf("unexpected record %+v", r)
+	}
+	if records[1].Existed {
+		t.Error("expected new key to not have existed")
+	}
+	if records[2].Op != "save" || records[2].Path != path {
+		t.Errorf("unexpected save record %+v", records[2])
+	}
+
+	var log bytes.Buffer
+	f.Set("s", "k", "newer", AuditLog("tester", &log))
+	if !bytes.HasSuffix(log.Bytes(), []byte(` tester set [s] k: "new" -> "newer"`+"\n")) {
+		t.Errorf("unexpected log line %q", log.String())
+	}
+
+	if back, err := Load(path); err != nil || !reflect.DeepEqual(back, MustParse("[s]\nk = new\nk2 = v")) {
+		t.Errorf("saved file: got %v, %v", back, err)
+	}
+}
+
+func TestNoSections(t *testing.T) {
+	f, err := Read(strings.NewReader("# flags\nPORT = 80\nHOST=x"), NoSections())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(f, File{"": {"PORT": "80", "HOST": "x"}}) {
+		t.Errorf("unexpected File %v", f)
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf, NoSections()); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "HOST = x\nPORT = 80\n" {
+		t.Errorf("unexpected flat output %q", buf.String())
+	}
+
+	_, err = Read(strings.NewReader("a = 1\n[server]\nb = 2"), NoSections())
+	if err != (ErrSectionNotAllowed{2, "server"}) {
+		t.Errorf("expected ErrSectionNotAllowed, got %v", err)
+	}
+	err = MustParse("[server]\nb = 2").Write(&buf, NoSections())
+	if err != (ErrSectionNotAllowed{Section: "server"}) {
+		t.Errorf("expected ErrSectionNotAllowed, got %v", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	a := "; settings\n[b]\n  y=2\n  x = 1\n[a]\nkey:value\n"
+	b := "[a]\r\nkey = value\r\n\r\n[b]\r\nx=1\r\ny =   2"
+	na, err := Normalize(strings.NewReader(a), Delimiters("=:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nb, err := Normalize(strings.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "[a]\nkey = value\n\n[b]\nx = 1\ny = 2\n"
+	if string(na) != expect || string(nb) != expect {
+		t.Errorf("expected both to normalize to %q, got %q and %q", expect, na, nb)
+	}
+
+	if _, err := Normalize(strings.NewReader("wut?")); err == nil {
+		t.Error("expected syntax error")
+	}
+}
+
+func TestAnnotateOrigins(t *testing.T) {
+	l := WithDefaults(MustParse("[net]\ntimeout = 5"), map[string]map[string]string{
+		"net": {"timeout": "30", "retries": "3"},
+	})
+	l.Stack[1].Name = "/etc/app.ini"
+	l.Stack = append(l.Stack, Layer{File: MustParse("[net]\nproxy = none")})
+
+	var buf strings.Builder
+	if err := l.File().Write(&buf, AnnotateOrigins(l.Origins())); err != nil {
+		t.Fatal(err)
+	}
+	expect := "[net]\nproxy = none\n; from defaults\nretries = 3\n; from /etc/app.ini\ntimeout = 5\n"
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, buf.String())
+	}
+
+	buf.Reset()
+	if err := MustParse("a = 1").Write(&buf, AnnotateOrigins(Origins{"": {"a": "env"}}), CommentPrefixes("#")); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "# from env\na = 1\n" {
+		t.Errorf("unexpected output\n%s", buf.String())
+	}
+}
+
+func TestWriteSections(t *testing.T) {
+	f := MustParse("top = 1\n[database]\nhost = db\nport = 5432\n[cache]\nsize = 10\n[log]\nlevel = info")
+
+	var buf strings.Builder
+	n, err := f.Section("database").WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "host = db\nport = 5432\n" || n != int64(buf.Len()) {
+		t.Errorf("unexpected section output (%d bytes)\n%s", n, buf.String())
+	}
+
+	buf.Reset()
+	if err := f.WriteSections(&buf, "log", "missing", "", "database"); err != nil {
+		t.Fatal(err)
+	}
+	expect := "[log]\nlevel = info\n\ntop = 1\n\n[database]\nhost = db\nport = 5432\n"
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, buf.String())
+	}
+}
+
+func TestWriteSectionsOptions(t *testing.T) {
+	var order Order
+	f, err := Read(strings.NewReader("[s]\nz = 1\na = \" x \""), KeepOrder(&order), QuotedValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf strings.Builder
+	if err := f.WriteSectionsWith(&buf, []string{"s"}, KeepOrder(&order), QuotedValues()); err != nil {
+		t.Fatal(err)
+	}
+	expect := "[s]\nz = 1\na = \" x \"\n"
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, buf.String())
+	}
+
+	buf.Reset()
+	if err := f.Section("s").Write(&buf, "", QuotedValues()); err != nil {
+		t.Fatal(err)
+	}
+	expect = "a = \" x \"\nz = 1\n"
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, buf.String())
+	}
+
+	buf.Reset()
+	origins := Origins{"s": {"a": "a.ini"}}
+	if err := f.Section("s").Write(&buf, "s", KeepOrder(&order), AnnotateOrigins(origins), QuotedValues()); err != nil {
+		t.Fatal(err)
+	}
+	expect = "z = 1\n; from a.ini\na = \" x \"\n"
+	if buf.String() != expect {
+		t.Errorf("expected\n%s\ngot\n%s", expect, buf.String())
+	}
+
+	err = File{"s": {"k": "a\nb"}}.WriteSections(&buf, "s")
+	if _, ok := err.(ErrNotWritable); !ok {
+		t.Errorf("expected ErrNotWritable but got %v", err)
+	}
+}
+
+func TestWriteLineBreaks(t *testing.T) {
+	indent := Continuations(IndentContinuation)
+	tests := []struct {
+		value  string
+		opts   []Option
+		reason string
+	}{
+		{"a\nb", nil, "line breaks need Continuations(IndentContinuation)"},
+		{"a\nb", []Option{DialectGit}, "line breaks need Continuations(IndentContinuation)"},
+		{"a\n\nb", []Option{indent}, "a blank line would end the value"},
+		{"a\n  \nb", []Option{indent}, "a blank line would end the value"},
+		{"a\n; b", []Option{indent}, "a line starting with ; would be a comment"},
+		{"a\nb\n", []Option{indent}, "a blank line would end the value"},
+	}
+	for _, test := range tests {
+		f := File{"s": {"k": test.value}}
+		err := f.Write(ioutil.Discard, test.opts...)
+		if err != (ErrNotWritable{"s", "k", test.reason}) {
+			t.Errorf("%q: expected %q, got %v", test.value, test.reason, err)
+		}
+		doc, _ := ParseDocument(strings.NewReader(""), test.opts...)
+		if err := doc.Set("s", "k", test.value); err != (ErrNotWritable{"s", "k", test.reason}) {
+			t.Errorf("Document %q: expected %q, got %v", test.value, test.reason, err)
+		}
+		if len(doc.Nodes) != 0 {
+			t.Errorf("Document %q: expected no change, got %v", test.value, doc.Nodes)
+		}
+	}
+
+	f := File{"s": {"k": "a\nb  c\nd"}}
+	var buf bytes.Buffer
+	if err := f.Write(&buf, indent); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "[s]\nk = a\n    b  c\n    d\n"; buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
+
+func TestWriteUnreadable(t *testing.T) {
+	indent := Continuations(IndentContinuation)
+	tests := []struct {
+		key, value string
+		opts       []Option
+		reason     string
+	}{
+		{"a=b", "c", nil, "the key contains the delimiter ="},
+		{"a:b", "c", []Option{Delimiters("=:")}, "the key contains the delimiter :"},
+		{"", "c", nil, "the key is empty"},
+		{"a\nb", "c", nil, "the key contains a line break"},
+		{" a", "c", nil, "spaces around the key would be removed"},
+		{"# a", "c", nil, "a key starting with # would be a comment"},
+		{";a", "c", nil, "a key starting with ; would be a comment"},
+		{"[a]", "c", nil, "a key starting with [ would look like a section header"},
+		{"a", " c", nil, "spaces around the value would be removed, they need QuotedValues"},
+		{"a", "c\t", nil, "spaces around the value would be removed, they need QuotedValues"},
+		{"a", "x\n  b", []Option{indent}, "spaces around the lines of the value would be removed"},
+		{"a", " x\nb", []Option{indent, QuotedValues()}, "spaces around the lines of the value would be removed"},
+	}
+	for _, test := range tests {
+		f := File{"s": {test.key: test.value}}
+		err := f.Write(ioutil.Discard, test.opts...)
+		if err != (ErrNotWritable{"s", test.key, test.reason}) {
+			t.Errorf("%q = %q: expected %q, got %v", test.key, test.value, test.reason, err)
+		}
+		doc, _ := ParseDocument(strings.NewReader(""), test.opts...)
+		if err := doc.Set("s", test.key, test.value); err != (ErrNotWritable{"s", test.key, test.reason}) {
+			t.Errorf("Document %q = %q: expected %q, got %v", test.key, test.value, test.reason, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := (File{"s": {"a": " c "}}).Write(&buf, QuotedValues()); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "[s]\na = \" c \"\n"; buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+}
+
+func TestWriteDialect(t *testing.T) {
+	if same, diff, err := RoundTrip([]byte("a: b\nc:\n"), Delimiters(":")); err != nil || !same {
+		t.Errorf("expected round trip with : delimiter, got %v, %s, %v", same, diff, err)
+	}
+	var buf bytes.Buffer
+	if err := (File{"s": {"a": "b", "c": ""}}).Write(&buf, Delimiters(":=")); err != nil {
+		t.Fatal(err)
+	}
+	if expect := "[s]\na: b\nc:\n"; buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+
+	doc, err := ParseDocument(strings.NewReader("[s]\nx: 1\n"), Delimiters(":"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Set("s", "y", "2")
+	buf.Reset()
+	doc.WriteTo(&buf)
+	if expect := "[s]\nx: 1\ny: 2\n"; buf.String() != expect {
+		t.Errorf("expected %q, got %q", expect, buf.String())
+	}
+
+	err = (File{"Service": {"ExecStart": `run \`}}).Write(ioutil.Discard, DialectSystemd)
+	if err != (ErrNotWritable{"Service", "ExecStart", `a trailing \ would continue the value`}) {
+		t.Errorf("unexpected error %v", err)
+	}
+}